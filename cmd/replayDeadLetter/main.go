@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/paper-trade-chatbot/be-common/cache"
+	"github.com/paper-trade-chatbot/be-common/config"
+	"github.com/paper-trade-chatbot/be-common/database"
+	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-match/lib/tradingCalendar"
+	"github.com/paper-trade-chatbot/be-match/models/mqModels"
+	"github.com/paper-trade-chatbot/be-match/pubsub/deadLetter"
+	"github.com/paper-trade-chatbot/be-match/pubsub/matchClosePosition"
+	"github.com/paper-trade-chatbot/be-match/pubsub/matchOpenPosition"
+	"github.com/paper-trade-chatbot/be-match/service"
+)
+
+// replayDeadLetter 問題修正後, 將選取的死信重新送進撮合
+//
+//	replayDeadLetter -dataset openPosition -dry-run
+//	replayDeadLetter -dataset openPosition -orders 101,102
+//	replayDeadLetter -dataset closePosition -codes 14 -limit 50
+func main() {
+	dataset := flag.String("dataset", "", "openPosition or closePosition")
+	orders := flag.String("orders", "", "comma separated order ids to replay")
+	failCodes := flag.String("codes", "", "comma separated fail codes to replay")
+	all := flag.Bool("all", false, "replay all dead letters")
+	limit := flag.Int("limit", 0, "max number of dead letters to replay, 0 for no limit")
+	dryRun := flag.Bool("dry-run", false, "list dead letters without replaying")
+	flag.Parse()
+
+	orderIDs, err := parseIDs(*orders)
+	if err != nil {
+		fmt.Println("invalid -orders:", err)
+		os.Exit(2)
+	}
+	codes, err := parseIDs(*failCodes)
+	if err != nil {
+		fmt.Println("invalid -codes:", err)
+		os.Exit(2)
+	}
+	if !*dryRun && !*all && len(orderIDs) == 0 && len(codes) == 0 {
+		fmt.Println("select dead letters with -orders, -codes or -all, or list them with -dry-run")
+		os.Exit(2)
+	}
+
+	var handle func(context.Context, *deadLetter.Letter) error
+	switch *dataset {
+	case deadLetter.Dataset_OpenPosition:
+		handle = func(ctx context.Context, letter *deadLetter.Letter) error {
+			model := &mqModels.OpenPositionModel{}
+			if err := json.Unmarshal(letter.Body, model); err != nil {
+				return err
+			}
+			return matchOpenPosition.MatchOpenPosition(ctx, model)
+		}
+	case deadLetter.Dataset_ClosePosition:
+		handle = func(ctx context.Context, letter *deadLetter.Letter) error {
+			model := &mqModels.ClosePositionModel{}
+			if err := json.Unmarshal(letter.Body, model); err != nil {
+				return err
+			}
+			model.TransactionType = letter.TransactionType
+			return matchClosePosition.MatchClosePosition(ctx, model)
+		}
+	default:
+		fmt.Println("invalid -dataset:", *dataset)
+		os.Exit(2)
+	}
+
+	selected := func(letter *deadLetter.Letter) bool {
+		if *all {
+			return true
+		}
+		return orderIDs[letter.OrderID] || codes[uint64(letter.FailCode)]
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logging.Initialize(ctx)
+	defer logging.Finalize()
+
+	cache.Initialize(ctx)
+	defer cache.Finalize()
+
+	database.Initialize(ctx)
+	defer database.Finalize()
+
+	service.Initialize(ctx)
+	defer service.Finalize(ctx)
+
+	tradingCalendar.Initialize(ctx)
+
+	if err := deadLetter.Initialize(
+		ctx,
+		config.GetString("RABBITMQ_USERNAME"),
+		config.GetString("RABBITMQ_PASSWORD"),
+		config.GetString("RABBITMQ_HOST"),
+		config.GetString("RABBITMQ_VIRTUAL_HOST"),
+		config.GetString("SERVICE_NAME"),
+	); err != nil {
+		fmt.Println("failed to connect dead letter exchange:", err)
+		os.Exit(1)
+	}
+	defer deadLetter.Finalize(ctx)
+
+	result, err := deadLetter.Replay(ctx, *dataset, selected, *limit, *dryRun, handle)
+	if result != nil {
+		printLetters("replayed", result.Replayed)
+		printLetters("failed again", result.Failed)
+		printLetters("kept", result.Kept)
+	}
+	if err != nil {
+		fmt.Println("replay stopped:", err)
+		os.Exit(1)
+	}
+}
+
+func parseIDs(s string) (map[uint64]bool, error) {
+	ids := map[uint64]bool{}
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids[id] = true
+	}
+	return ids, nil
+}
+
+func printLetters(title string, letters []*deadLetter.Letter) {
+	fmt.Printf("%s: %d\n", title, len(letters))
+	for _, l := range letters {
+		fmt.Printf("  order %d\tcode %d\treplayed %d\tfailed at %s\t%s\n", l.OrderID, l.FailCode, l.ReplayCount, l.FailedAt.Format("2006-01-02 15:04:05"), l.FailReason)
+	}
+}
@@ -13,17 +13,56 @@ import (
 	"github.com/gofrs/uuid"
 	"github.com/paper-trade-chatbot/be-common/cache"
 	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-match/cronjob/expirePendingOrder"
+	"github.com/paper-trade-chatbot/be-match/cronjob/financingCharge"
+	"github.com/paper-trade-chatbot/be-match/cronjob/reconciliation"
 )
 
+var scheduler *gocron.Scheduler
+
 func Cron() {
 
-	scheduler := gocron.NewScheduler(time.UTC)
+	scheduler = gocron.NewScheduler(time.UTC)
+
+	scheduler.Every(1).Minute().Do(work, expirePendingOrder.ExpirePendingOrder, func() string {
+		return "expirePendingOrder:" + time.Now().UTC().Format("200601021504")
+	}, time.Minute)
+
+	// 每日UTC 21:00收取隔夜融資費用
+	scheduler.Every(1).Day().At("21:00").Do(work, financingCharge.FinancingCharge, func() string {
+		return "financingCharge:" + time.Now().UTC().Format("20060102")
+	}, time.Hour)
+
+	// 每小時比對撮合紀錄與錢包交易及訂單
+	scheduler.Every(1).Hour().Do(work, reconciliation.Reconciliation, func() string {
+		return "reconciliation:" + time.Now().UTC().Format("2006010215")
+	}, 30*time.Minute)
 
 	// Start all the pending jobs
 	scheduler.StartAsync()
 
 }
 
+// Stop 停止排程並等待執行中的cronjob結束, 超過ctx期限時不再等待
+func Stop(ctx context.Context) error {
+	if scheduler == nil {
+		return nil
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		scheduler.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func work(cronjob func(context.Context) error, generateKey func() string, maxDuration time.Duration) {
 
 	cronjobID, _ := uuid.NewV4()
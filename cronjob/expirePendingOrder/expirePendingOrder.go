@@ -0,0 +1,127 @@
+package expirePendingOrder
+
+import (
+	"context"
+	"time"
+
+	"github.com/paper-trade-chatbot/be-common/database"
+	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-match/dao/matchRecordDao"
+	"github.com/paper-trade-chatbot/be-match/dao/pendingOrderDao"
+	"github.com/paper-trade-chatbot/be-match/lib/matchError"
+	"github.com/paper-trade-chatbot/be-match/lib/matchLock"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/paper-trade-chatbot/be-match/pubsub/matchEvent"
+	"github.com/paper-trade-chatbot/be-match/service"
+	"github.com/paper-trade-chatbot/be-proto/order"
+	"github.com/paper-trade-chatbot/be-proto/position"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// ExpirePendingOrder 取消已到期的DAY及GTD掛單
+func ExpirePendingOrder(ctx context.Context) error {
+	db := database.GetDB()
+	now := time.Now()
+
+	pendingOrders, err := pendingOrderDao.Gets(db, &pendingOrderDao.QueryModel{
+		PendingStatus: dbModels.PendingStatus_Pending,
+		ExpireBefore:  &now,
+	})
+	if err != nil {
+		logging.Error(ctx, "[ExpirePendingOrder] failed to get pendingOrders: %v", err)
+		return err
+	}
+
+	for i := range pendingOrders {
+		expire(ctx, db, &pendingOrders[i])
+	}
+	return nil
+}
+
+func expire(ctx context.Context, db *gorm.DB, p *dbModels.PendingOrderModel) {
+
+	// 與撮合取相同的鎖, 不取消正在撮合中的掛單
+	keys := []string{matchLock.MemberKey(p.MemberID)}
+	if p.TransactionType != dbModels.TransactionType_OpenPosition && p.PositionID.Valid {
+		keys = append(keys, matchLock.PositionKey(uint64(p.PositionID.Int64)))
+	}
+	locks, err := matchLock.AcquireAll(ctx, p.OrderID, keys...)
+	if err != nil {
+		logging.Error(ctx, "[ExpirePendingOrder] failed to lock order [%d]: %v", p.OrderID, err)
+		return
+	}
+	defer locks.Release()
+
+	expired := false
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		pendingStatus := dbModels.PendingStatus_Cancelled
+		ok, err := pendingOrderDao.ModifyIfPending(tx, p, &pendingOrderDao.UpdateModel{
+			PendingStatus: &pendingStatus,
+		})
+		if err != nil || !ok {
+			// 已被撮合或取消
+			return err
+		}
+
+		matchRecord, err := matchRecordDao.Get(tx, &matchRecordDao.QueryModel{
+			ID: p.MatchRecordID,
+		})
+		if err != nil {
+			return err
+		}
+
+		matchStatus := dbModels.MatchStatus_Cancelled
+		expireOutcome := dbModels.ExpireOutcome_Expired
+		if err := matchRecordDao.Modify(tx, &dbModels.MatchRecordModel{ID: p.MatchRecordID}, &matchRecordDao.UpdateModel{
+			MatchStatus:   &matchStatus,
+			ExpireOutcome: &expireOutcome,
+		}); err != nil {
+			return err
+		}
+
+		if matchRecord != nil {
+			matchRecord.MatchStatus = matchStatus
+			if err := matchEvent.Finished(tx, matchRecord, matchError.ErrOrderExpired); err != nil {
+				return err
+			}
+		}
+
+		expired = true
+		return nil
+	}); err != nil {
+		logging.Error(ctx, "[ExpirePendingOrder] failed to expire pendingOrder [%d]: %v", p.OrderID, err)
+		return
+	}
+	if !expired {
+		return
+	}
+
+	logging.Info(ctx, "[ExpirePendingOrder] order [%d] expired at %s", p.OrderID, p.ExpireAt.Time.String())
+
+	failCode := uint64(status.Code(matchError.ErrOrderExpired))
+	s, _ := status.FromError(matchError.ErrOrderExpired)
+	remark := s.Message()
+	if _, err := service.Impl.OrderIntf.FailOrder(ctx, &order.FailOrderReq{
+		Id:       p.OrderID,
+		FailCode: &failCode,
+		Remark:   &remark,
+	}); err != nil {
+		logging.Error(ctx, "[ExpirePendingOrder] failed to FailOrder [%d]: %v", p.OrderID, err)
+	}
+
+	if p.TransactionType == dbModels.TransactionType_ClosePosition {
+		if _, err := service.Impl.PositionIntf.StopPendingPosition(ctx, &position.StopPendingPositionReq{
+			Id: uint64(p.PositionID.Int64),
+		}); err != nil {
+			logging.Error(ctx, "[ExpirePendingOrder] StopPendingPosition failed: %v", err)
+		}
+	}
+
+	if _, err := service.Impl.OrderIntf.UpdateOrderProcess(ctx, &order.UpdateOrderProcessReq{
+		Id:           p.OrderID,
+		OrderProcess: order.OrderProcess_OrderProcess_Failed,
+	}); err != nil {
+		logging.Error(ctx, "[ExpirePendingOrder] failed to Update OrderProcess [%d]: %v", p.OrderID, err)
+	}
+}
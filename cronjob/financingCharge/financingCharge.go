@@ -0,0 +1,298 @@
+package financingCharge
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	common "github.com/paper-trade-chatbot/be-common"
+	"github.com/paper-trade-chatbot/be-common/database"
+	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-match/dao/financingChargeDao"
+	"github.com/paper-trade-chatbot/be-match/dao/financingRuleDao"
+	"github.com/paper-trade-chatbot/be-match/dao/positionMarginDao"
+	"github.com/paper-trade-chatbot/be-match/lib/financing"
+	"github.com/paper-trade-chatbot/be-match/lib/marketPrice"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/paper-trade-chatbot/be-match/service"
+	"github.com/paper-trade-chatbot/be-proto/general"
+	"github.com/paper-trade-chatbot/be-proto/position"
+	"github.com/paper-trade-chatbot/be-proto/wallet"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	pageSize = 100
+	// staleAfter 處理中超過此時間視為中斷, 可重新收取
+	staleAfter = 10 * time.Minute
+)
+
+// FinancingCharge 對開槓桿的多倉及所有空倉收取一日的隔夜融資費用
+// 每個倉位每日只會收取一次, 重複執行時以financing_charge的唯一索引略過
+// 先重試失敗及中斷的紀錄, 再依部位服務的持倉收取當日費用
+func FinancingCharge(ctx context.Context) error {
+	db := database.GetDB()
+	chargeDate := time.Now().UTC().Truncate(24 * time.Hour)
+
+	if err := retryCharges(ctx, db); err != nil {
+		logging.Error(ctx, "[FinancingCharge] failed to retry financingCharges: %v", err)
+	}
+
+	status := position.PositionStatus_PositionStatus_Open
+	for page := int32(1); ; page++ {
+		positionRes, err := service.Impl.PositionIntf.GetPositions(ctx, &position.GetPositionsReq{
+			Status: &status,
+			Pagination: &general.Pagination{
+				Page:     page,
+				PageSize: pageSize,
+			},
+		})
+		if err != nil {
+			logging.Error(ctx, "[FinancingCharge] failed to get positions: %v", err)
+			return err
+		}
+
+		for _, p := range positionRes.Positions {
+			pm, err := toPositionMargin(db, p)
+			if err != nil {
+				logging.Error(ctx, "[FinancingCharge] failed to get margin of position [%d]: %v", p.Id, err)
+				continue
+			}
+			if pm.TradeType != dbModels.TradeType_Sell && !pm.Leverage.GreaterThan(decimal.NewFromInt(1)) {
+				continue
+			}
+			if err := charge(ctx, db, pm, chargeDate); err != nil {
+				logging.Error(ctx, "[FinancingCharge] failed to charge position [%d]: %v", pm.PositionID, err)
+			}
+		}
+		if len(positionRes.Positions) < pageSize {
+			break
+		}
+	}
+	return nil
+}
+
+// toPositionMargin 以持倉資料組成計息用的保證金資料, 未經保證金占用的倉位視為未開槓桿
+func toPositionMargin(db *gorm.DB, p *position.Position) (*dbModels.PositionMarginModel, error) {
+	amount, err := decimal.NewFromString(p.Amount)
+	if err != nil {
+		return nil, err
+	}
+	openPrice, err := decimal.NewFromString(p.UnitPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	pm := &dbModels.PositionMarginModel{
+		PositionID:   p.Id,
+		MemberID:     p.MemberID,
+		ExchangeCode: p.ExchangeCode,
+		ProductCode:  p.ProductCode,
+		TradeType:    dbModels.TradeType(p.TradeType),
+		MarginStatus: dbModels.MarginStatus_Open,
+		Leverage:     decimal.NewFromInt(1),
+		OpenPrice:    openPrice,
+		Amount:       amount,
+		Margin:       openPrice.Mul(amount),
+	}
+
+	margin, err := positionMarginDao.Get(db, &positionMarginDao.QueryModel{
+		PositionID:   p.Id,
+		MarginStatus: dbModels.MarginStatus_Open,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if margin != nil {
+		pm.Leverage = margin.Leverage
+		pm.Margin = margin.Margin
+	}
+	return pm, nil
+}
+
+// retryCharges 重新收取失敗或處理中斷的費用, 沿用原紀錄的金額
+func retryCharges(ctx context.Context, db *gorm.DB) error {
+	staleBefore := time.Now().Add(-staleAfter)
+
+	failed, err := financingChargeDao.Gets(db, &financingChargeDao.QueryModel{
+		FinancingStatus: dbModels.FinancingStatus_Failed,
+	})
+	if err != nil {
+		return err
+	}
+	stale, err := financingChargeDao.Gets(db, &financingChargeDao.QueryModel{
+		FinancingStatus: dbModels.FinancingStatus_Processing,
+		UpdatedBefore:   &staleBefore,
+	})
+	if err != nil {
+		return err
+	}
+
+	records := append(failed, stale...)
+	for i := range records {
+		record := &records[i]
+		processing := dbModels.FinancingStatus_Processing
+		ok, err := financingChargeDao.ModifyIfRetryable(db, record, staleBefore, &financingChargeDao.UpdateModel{
+			FinancingStatus: &processing,
+		})
+		if err != nil {
+			logging.Error(ctx, "[FinancingCharge] failed to claim financingCharge [%d]: %v", record.ID, err)
+			continue
+		}
+		if !ok {
+			// 已由其他程序處理
+			continue
+		}
+		if err := pay(ctx, db, record, true); err != nil {
+			logging.Error(ctx, "[FinancingCharge] failed to retry financingCharge [%d]: %v", record.ID, err)
+		}
+	}
+	return nil
+}
+
+func charge(ctx context.Context, db *gorm.DB, pm *dbModels.PositionMarginModel, chargeDate time.Time) error {
+	rule, err := financingRuleDao.GetByProduct(db, pm.ExchangeCode, pm.ProductCode)
+	if err != nil {
+		return err
+	}
+	if rule == nil {
+		return nil
+	}
+	rate := financing.Rate(rule, pm.TradeType)
+	if !rate.IsPositive() {
+		return nil
+	}
+
+	productRes, err := marketPrice.GetProduct(ctx, pm.ExchangeCode, pm.ProductCode)
+	if err != nil {
+		return err
+	}
+	unitPrice, err := marketPrice.GetPrice(ctx, productRes.Id, pm.TradeType, dbModels.TransactionType_ClosePosition)
+	if err != nil {
+		return err
+	}
+
+	principal := financing.Principal(pm, unitPrice)
+	fee := financing.DailyFee(rule, principal, rate)
+	if !fee.IsPositive() {
+		return nil
+	}
+
+	record := &dbModels.FinancingChargeModel{
+		PositionID:      pm.PositionID,
+		ChargeDate:      chargeDate,
+		MemberID:        pm.MemberID,
+		ExchangeCode:    pm.ExchangeCode,
+		ProductCode:     pm.ProductCode,
+		TradeType:       pm.TradeType,
+		FinancingStatus: dbModels.FinancingStatus_Processing,
+		UnitPrice:       unitPrice,
+		Amount:          pm.Amount,
+		Principal:       principal,
+		Rate:            rate,
+		Fee:             fee,
+	}
+	ok, err := financingChargeDao.NewIfNotExist(db, record)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		// 當日已收取
+		return nil
+	}
+
+	return pay(ctx, db, record, false)
+}
+
+// pay 自錢包扣除紀錄的費用並更新紀錄狀態
+// 重試時先以交易備註查詢是否已扣款, 避免中斷後重複收取
+func pay(ctx context.Context, db *gorm.DB, record *dbModels.FinancingChargeModel, retry bool) error {
+	financingStatus := dbModels.FinancingStatus_Failed
+	transactionID := sql.NullInt64{}
+	defer func() {
+		if err := financingChargeDao.Modify(db, record, &financingChargeDao.UpdateModel{
+			FinancingStatus: &financingStatus,
+			TransactionID:   &transactionID,
+		}); err != nil {
+			logging.Error(ctx, "[FinancingCharge] failed to Modify financingCharge [%d]: %v", record.ID, err)
+		}
+	}()
+
+	remark := fmt.Sprintf("financing of position %d on %s", record.PositionID, record.ChargeDate.Format("2006-01-02"))
+	if retry {
+		charged, err := findTransaction(ctx, record, remark)
+		if err != nil {
+			return err
+		}
+		if charged != nil {
+			financingStatus = dbModels.FinancingStatus_Charged
+			transactionID = sql.NullInt64{Valid: true, Int64: int64(charged.Id)}
+			logging.Warn(ctx, "[FinancingCharge] financingCharge [%d] was already charged by transaction [%d]", record.ID, charged.Id)
+			return nil
+		}
+	}
+
+	productRes, err := marketPrice.GetProduct(ctx, record.ExchangeCode, record.ProductCode)
+	if err != nil {
+		return err
+	}
+
+	walletRes, err := service.Impl.WalletIntf.GetWallets(ctx, &wallet.GetWalletsReq{
+		Wallet: &wallet.GetWalletsReq_MemberID{
+			MemberID: record.MemberID,
+		},
+		Currency: &productRes.CurrencyCode,
+	})
+	if err != nil {
+		return err
+	}
+	if len(walletRes.Wallets) == 0 {
+		return common.ErrNoSuchWallet
+	}
+
+	transactionRes, err := service.Impl.WalletIntf.Transaction(ctx, &wallet.TransactionReq{
+		WalletID:    walletRes.Wallets[0].Id,
+		Action:      wallet.Action_Action_INTEREST,
+		Amount:      record.Fee.Neg().String(),
+		Currency:    productRes.CurrencyCode,
+		CommitterID: record.MemberID,
+		Remark:      &remark,
+	})
+	if err != nil {
+		return err
+	}
+
+	financingStatus = dbModels.FinancingStatus_Charged
+	transactionID = sql.NullInt64{Valid: true, Int64: int64(transactionRes.Id)}
+	logging.Info(ctx, "[FinancingCharge] charged %s for position [%d]", record.Fee.String(), record.PositionID)
+	return nil
+}
+
+// findTransaction 查詢紀錄建立後是否已有相同備註的成功扣款
+func findTransaction(ctx context.Context, record *dbModels.FinancingChargeModel, remark string) (*wallet.TransactionRecord, error) {
+	createdFrom := record.CreatedAt.Unix()
+	for page := int32(1); ; page++ {
+		res, err := service.Impl.WalletIntf.GetTransactionRecords(ctx, &wallet.GetTransactionRecordsReq{
+			MemberID:    &record.MemberID,
+			Action:      []wallet.Action{wallet.Action_Action_INTEREST},
+			CreatedFrom: &createdFrom,
+			Pagination: &general.Pagination{
+				Page:     page,
+				PageSize: pageSize,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range res.Records {
+			if t.Remark != nil && *t.Remark == remark && t.Status == wallet.Status_Status_SUCCESS {
+				return t, nil
+			}
+		}
+		if len(res.Records) < pageSize {
+			return nil, nil
+		}
+	}
+}
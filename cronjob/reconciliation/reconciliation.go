@@ -0,0 +1,339 @@
+package reconciliation
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/paper-trade-chatbot/be-common/database"
+	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-match/dao/matchRecordDao"
+	"github.com/paper-trade-chatbot/be-match/dao/reconciliationDiscrepancyDao"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/paper-trade-chatbot/be-match/service"
+	"github.com/paper-trade-chatbot/be-proto/general"
+	"github.com/paper-trade-chatbot/be-proto/order"
+	"github.com/paper-trade-chatbot/be-proto/wallet"
+	"gorm.io/gorm"
+)
+
+const (
+	// reconcileWindow 每次對帳的撮合紀錄建立時間範圍, 比排程間隔長, 前後兩次會重疊
+	reconcileWindow = 2 * time.Hour
+	// reconcileGrace 最近建立的撮合紀錄可能仍在撮合中, 交給recoverMatch處理
+	reconcileGrace = 10 * time.Minute
+	pageSize       = 100
+)
+
+type reconciler struct {
+	ctx          context.Context
+	db           *gorm.DB
+	autoRepair   bool
+	orders       map[uint64]*order.Order
+	transactions map[uint64]*wallet.TransactionRecord
+}
+
+// Reconciliation 比對撮合紀錄與訂單服務的訂單及錢包服務的交易, 差異寫入reconciliation_discrepancy
+// 設定RECONCILIATION_AUTO_REPAIR時, 自動回滾撮合失敗但未回滾的扣款, 其餘差異留待人工處理
+func Reconciliation(ctx context.Context) error {
+	db := database.GetDB()
+	now := time.Now()
+	to := now.Add(-reconcileGrace)
+	from := to.Add(-reconcileWindow)
+
+	legType := dbModels.LegType_None
+	matchRecords, err := matchRecordDao.Gets(db, &matchRecordDao.QueryModel{
+		LegType:     &legType,
+		CreatedFrom: &from,
+		CreatedTo:   &to,
+	})
+	if err != nil {
+		logging.Error(ctx, "[Reconciliation] failed to get matchRecords: %v", err)
+		return err
+	}
+
+	records := []*dbModels.MatchRecordModel{}
+	recordsByOrder := map[uint64]*dbModels.MatchRecordModel{}
+	orderIDs := []uint64{}
+	memberIDs := []uint64{}
+	members := map[uint64]bool{}
+	for i := range matchRecords {
+		record := &matchRecords[i]
+		if record.MatchStatus == dbModels.MatchStatus_Pending {
+			continue
+		}
+		records = append(records, record)
+		recordsByOrder[record.OrderID] = record
+		orderIDs = append(orderIDs, record.OrderID)
+		if !members[record.MemberID] {
+			members[record.MemberID] = true
+			memberIDs = append(memberIDs, record.MemberID)
+		}
+	}
+
+	orders, err := getOrders(ctx, orderIDs)
+	if err != nil {
+		logging.Error(ctx, "[Reconciliation] failed to get orders: %v", err)
+		return err
+	}
+
+	// 交易在撮合紀錄建立之後才產生, 查詢到目前為止的交易
+	transactions, err := getTransactions(ctx, memberIDs, from, now)
+	if err != nil {
+		logging.Error(ctx, "[Reconciliation] failed to get transactions: %v", err)
+		return err
+	}
+
+	r := &reconciler{
+		ctx:          ctx,
+		db:           db,
+		autoRepair:   autoRepairEnabled(),
+		orders:       orders,
+		transactions: transactions,
+	}
+
+	referenced := map[uint64]bool{}
+	for _, record := range records {
+		r.checkRecord(record, referenced)
+	}
+
+	for _, t := range transactions {
+		if referenced[t.Id] || t.Status != wallet.Status_Status_SUCCESS {
+			continue
+		}
+		if time.Unix(t.CreatedAt, 0).Before(from) || !time.Unix(t.CreatedAt, 0).Before(to) {
+			continue
+		}
+		r.checkOrphan(t, recordsByOrder)
+	}
+
+	logging.Info(ctx, "[Reconciliation] checked %d matchRecords and %d transactions from %v to %v.", len(records), len(transactions), from, to)
+	return nil
+}
+
+func autoRepairEnabled() bool {
+	value, ok := os.LookupEnv("RECONCILIATION_AUTO_REPAIR")
+	if !ok {
+		return false
+	}
+	enabled, _ := strconv.ParseBool(value)
+	return enabled
+}
+
+func getOrders(ctx context.Context, orderIDs []uint64) (map[uint64]*order.Order, error) {
+	orders := map[uint64]*order.Order{}
+	for start := 0; start < len(orderIDs); start += pageSize {
+		end := start + pageSize
+		if end > len(orderIDs) {
+			end = len(orderIDs)
+		}
+		res, err := service.Impl.OrderIntf.GetOrders(ctx, &order.GetOrdersReq{
+			Id: orderIDs[start:end],
+			Pagination: &general.Pagination{
+				Page:     1,
+				PageSize: int32(end - start),
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range res.Orders {
+			orders[o.Id] = o
+		}
+	}
+	return orders, nil
+}
+
+func getTransactions(ctx context.Context, memberIDs []uint64, from, to time.Time) (map[uint64]*wallet.TransactionRecord, error) {
+	transactions := map[uint64]*wallet.TransactionRecord{}
+	createdFrom := from.Unix()
+	createdTo := to.Unix()
+	for i := range memberIDs {
+		for page := int32(1); ; page++ {
+			res, err := service.Impl.WalletIntf.GetTransactionRecords(ctx, &wallet.GetTransactionRecordsReq{
+				MemberID:    &memberIDs[i],
+				Action:      []wallet.Action{wallet.Action_Action_OPEN, wallet.Action_Action_CLOSE},
+				CreatedFrom: &createdFrom,
+				CreatedTo:   &createdTo,
+				Pagination: &general.Pagination{
+					Page:     page,
+					PageSize: pageSize,
+				},
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, t := range res.Records {
+				transactions[t.Id] = t
+			}
+			if len(res.Records) < pageSize {
+				break
+			}
+		}
+	}
+	return transactions, nil
+}
+
+// parseOrderID 撮合的交易備註為"order %d", 手續費為"fee of order %d"
+func parseOrderID(remark *string) (uint64, bool) {
+	if remark == nil {
+		return 0, false
+	}
+	index := strings.LastIndex(*remark, "order ")
+	if index < 0 {
+		return 0, false
+	}
+	orderID, err := strconv.ParseUint((*remark)[index+len("order "):], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return orderID, true
+}
+
+func isFailed(matchStatus dbModels.MatchStatus) bool {
+	return matchStatus == dbModels.MatchStatus_Failed ||
+		matchStatus == dbModels.MatchStatus_Rollbacked ||
+		matchStatus == dbModels.MatchStatus_Cancelled
+}
+
+func (r *reconciler) checkRecord(record *dbModels.MatchRecordModel, referenced map[uint64]bool) {
+	o, ok := r.orders[record.OrderID]
+	if !ok {
+		r.report(r.newDiscrepancy(dbModels.DiscrepancyType_OrderNotFound, record, nil, nil), nil)
+	} else {
+		orderFinished := o.OrderStatus == order.OrderStatus_OrderStatus_Finished
+		if (record.MatchStatus == dbModels.MatchStatus_Finished && !orderFinished) ||
+			(isFailed(record.MatchStatus) && orderFinished) {
+			r.report(r.newDiscrepancy(dbModels.DiscrepancyType_OrderStatusMismatch, record, o, nil), nil)
+		}
+	}
+
+	for _, transactionID := range []sql.NullInt64{record.TransactionID, record.FeeTransactionID} {
+		if !transactionID.Valid {
+			continue
+		}
+		referenced[uint64(transactionID.Int64)] = true
+
+		t, ok := r.transactions[uint64(transactionID.Int64)]
+		if !ok {
+			d := r.newDiscrepancy(dbModels.DiscrepancyType_TransactionNotFound, record, o, nil)
+			d.TransactionID = uint64(transactionID.Int64)
+			r.report(d, nil)
+			continue
+		}
+
+		if record.MatchStatus == dbModels.MatchStatus_Finished && t.Status != wallet.Status_Status_SUCCESS {
+			r.report(r.newDiscrepancy(dbModels.DiscrepancyType_TransactionNotSuccess, record, o, t), nil)
+		}
+
+		if isFailed(record.MatchStatus) && t.Status == wallet.Status_Status_SUCCESS {
+			var repair func() error
+			// 訂單確定未完成時才能安全回滾
+			if o != nil && o.OrderStatus != order.OrderStatus_OrderStatus_Finished {
+				repair = r.rollback(t)
+			}
+			r.report(r.newDiscrepancy(dbModels.DiscrepancyType_MissingRollback, record, o, t), repair)
+		}
+	}
+}
+
+// checkOrphan 交易已扣款但撮合紀錄沒有記下交易id, 例如扣款後當機或扣款回應逾時
+func (r *reconciler) checkOrphan(t *wallet.TransactionRecord, recordsByOrder map[uint64]*dbModels.MatchRecordModel) {
+	orderID, ok := parseOrderID(t.Remark)
+	if !ok {
+		d := r.newDiscrepancy(dbModels.DiscrepancyType_OrphanTransaction, nil, nil, t)
+		d.MemberID = t.MemberID
+		r.report(d, nil)
+		return
+	}
+
+	record, ok := recordsByOrder[orderID]
+	if !ok {
+		// 撮合紀錄不在本次範圍內, 無法判斷
+		return
+	}
+	o := r.orders[orderID]
+
+	var repair func() error
+	if isFailed(record.MatchStatus) && o != nil && o.OrderStatus != order.OrderStatus_OrderStatus_Finished {
+		repair = r.rollback(t)
+	}
+	r.report(r.newDiscrepancy(dbModels.DiscrepancyType_OrphanTransaction, record, o, t), repair)
+}
+
+func (r *reconciler) rollback(t *wallet.TransactionRecord) func() error {
+	return func() error {
+		_, err := service.Impl.WalletIntf.RollbackTransaction(r.ctx, &wallet.RollbackTransactionReq{
+			Id:           t.Id,
+			RollbackerID: t.MemberID,
+		})
+		return err
+	}
+}
+
+func (r *reconciler) newDiscrepancy(discrepancyType dbModels.DiscrepancyType, record *dbModels.MatchRecordModel, o *order.Order, t *wallet.TransactionRecord) *dbModels.ReconciliationDiscrepancyModel {
+	d := &dbModels.ReconciliationDiscrepancyModel{
+		DiscrepancyType: discrepancyType,
+		RepairStatus:    dbModels.RepairStatus_Manual,
+	}
+	if record != nil {
+		d.OrderID = record.OrderID
+		d.MatchRecordID = sql.NullInt64{Valid: true, Int64: int64(record.ID)}
+		d.MemberID = record.MemberID
+		d.MatchStatus = sql.NullInt32{Valid: true, Int32: int32(record.MatchStatus)}
+	}
+	if o != nil {
+		d.OrderStatus = sql.NullInt32{Valid: true, Int32: int32(o.OrderStatus)}
+	}
+	if t != nil {
+		d.TransactionID = t.Id
+		d.TransactionStatus = sql.NullInt32{Valid: true, Int32: int32(t.Status)}
+	}
+	return d
+}
+
+// report 寫入差異報表, 同一差異只記一次, 可修復的差異在自動修復失敗後下次再試
+func (r *reconciler) report(d *dbModels.ReconciliationDiscrepancyModel, repair func() error) {
+	inserted, err := reconciliationDiscrepancyDao.NewIfNotExist(r.db, d)
+	if err != nil {
+		logging.Error(r.ctx, "[Reconciliation] failed to new discrepancy of order [%d] transaction [%d]: %v", d.OrderID, d.TransactionID, err)
+		return
+	}
+	if inserted {
+		logging.Warn(r.ctx, "[Reconciliation] discrepancy [%d] of order [%d] transaction [%d].", d.DiscrepancyType, d.OrderID, d.TransactionID)
+	} else {
+		existing, err := reconciliationDiscrepancyDao.GetByKey(r.db, d.DiscrepancyType, d.OrderID, d.TransactionID)
+		if err != nil || existing == nil {
+			logging.Error(r.ctx, "[Reconciliation] failed to get discrepancy of order [%d] transaction [%d]: %v", d.OrderID, d.TransactionID, err)
+			return
+		}
+		if existing.RepairStatus == dbModels.RepairStatus_Repaired {
+			return
+		}
+		d = existing
+	}
+
+	if repair == nil || !r.autoRepair {
+		return
+	}
+
+	repairStatus := dbModels.RepairStatus_Repaired
+	remark := "rolled back automatically"
+	if err := repair(); err != nil {
+		logging.Error(r.ctx, "[Reconciliation] failed to repair discrepancy [%d]: %v", d.ID, err)
+		repairStatus = dbModels.RepairStatus_RepairFailed
+		remark = err.Error()
+		if len(remark) > 255 {
+			remark = remark[:255]
+		}
+	}
+	if err := reconciliationDiscrepancyDao.Modify(r.db, d, &reconciliationDiscrepancyDao.UpdateModel{
+		RepairStatus: &repairStatus,
+		Remark:       &remark,
+	}); err != nil {
+		logging.Error(r.ctx, "[Reconciliation] failed to modify discrepancy [%d]: %v", d.ID, err)
+	}
+}
@@ -0,0 +1,121 @@
+package financingChargeDao
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const table = "financing_charge"
+
+// QueryModel set query condition, used by queryChain()
+type QueryModel struct {
+	FinancingStatus dbModels.FinancingStatus
+	UpdatedBefore   *time.Time
+}
+
+type UpdateModel struct {
+	FinancingStatus *dbModels.FinancingStatus
+	TransactionID   *sql.NullInt64
+}
+
+// NewIfNotExist insert a row unless the position was already charged on that day,
+// returns false if the row exists
+func NewIfNotExist(db *gorm.DB, model *dbModels.FinancingChargeModel) (bool, error) {
+
+	result := db.Table(table).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(model)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// Gets return records as raw-data-form
+func Gets(tx *gorm.DB, query *QueryModel) ([]dbModels.FinancingChargeModel, error) {
+	result := make([]dbModels.FinancingChargeModel, 0)
+	err := tx.Table(table).
+		Scopes(queryChain(query)).
+		Scan(&result).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return []dbModels.FinancingChargeModel{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Modify update a record
+func Modify(tx *gorm.DB, model *dbModels.FinancingChargeModel, update *UpdateModel) error {
+	attrs := map[string]interface{}{}
+	if update.FinancingStatus != nil {
+		attrs["financing_status"] = *update.FinancingStatus
+	}
+	if update.TransactionID != nil {
+		attrs["transaction_id"] = *update.TransactionID
+	}
+
+	err := tx.Table(table).
+		Model(dbModels.FinancingChargeModel{}).
+		Where(table+".id = ?", model.ID).
+		Updates(attrs).Error
+
+	return err
+}
+
+// ModifyIfRetryable update a record only if it failed or has been processing since before staleBefore,
+// returns false if it was charged or taken by another process
+func ModifyIfRetryable(tx *gorm.DB, model *dbModels.FinancingChargeModel, staleBefore time.Time, update *UpdateModel) (bool, error) {
+	attrs := map[string]interface{}{}
+	if update.FinancingStatus != nil {
+		attrs["financing_status"] = *update.FinancingStatus
+	}
+	if update.TransactionID != nil {
+		attrs["transaction_id"] = *update.TransactionID
+	}
+	// 更新時間一併更新, 避免其他程序馬上又視為中斷
+	attrs["updated_at"] = time.Now()
+
+	result := tx.Table(table).
+		Model(dbModels.FinancingChargeModel{}).
+		Where(table+".id = ?", model.ID).
+		Where("("+table+".financing_status = ? OR ("+table+".financing_status = ? AND "+table+".updated_at < ?))",
+			dbModels.FinancingStatus_Failed, dbModels.FinancingStatus_Processing, staleBefore).
+		Updates(attrs)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func queryChain(query *QueryModel) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Scopes(financingStatusEqualScope(query.FinancingStatus)).
+			Scopes(updatedBeforeScope(query.UpdatedBefore))
+	}
+}
+
+func financingStatusEqualScope(financingStatus dbModels.FinancingStatus) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if financingStatus != dbModels.FinancingStatus_None {
+			return db.Where(table+".financing_status = ?", financingStatus)
+		}
+		return db
+	}
+}
+
+func updatedBeforeScope(updatedBefore *time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if updatedBefore != nil {
+			return db.Where(table+".updated_at < ?", *updatedBefore)
+		}
+		return db
+	}
+}
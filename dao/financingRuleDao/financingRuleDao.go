@@ -0,0 +1,32 @@
+package financingRuleDao
+
+import (
+	"errors"
+
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+
+	"gorm.io/gorm"
+)
+
+const table = "financing_rule"
+
+// GetByProduct return the most specific rule of a product:
+// product rule first, then exchange rule, then the global rule
+func GetByProduct(tx *gorm.DB, exchangeCode, productCode string) (*dbModels.FinancingRuleModel, error) {
+
+	result := &dbModels.FinancingRuleModel{}
+	err := tx.Table(table).
+		Where(table+".exchange_code IN (?, '')", exchangeCode).
+		Where(table+".product_code IN (?, '')", productCode).
+		Order(table + ".exchange_code DESC").
+		Order(table + ".product_code DESC").
+		First(result).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
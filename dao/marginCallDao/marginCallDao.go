@@ -0,0 +1,21 @@
+package marginCallDao
+
+import (
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+
+	"gorm.io/gorm"
+)
+
+const table = "margin_call"
+
+// New a row
+func New(db *gorm.DB, model *dbModels.MarginCallModel) (int, error) {
+
+	err := db.Table(table).
+		Create(model).Error
+
+	if err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
@@ -0,0 +1,29 @@
+package marginRuleDao
+
+import (
+	"errors"
+
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+
+	"gorm.io/gorm"
+)
+
+const table = "margin_rule"
+
+// GetByProductType return the rule of a product type, or the default rule
+func GetByProductType(tx *gorm.DB, productType dbModels.ProductType) (*dbModels.MarginRuleModel, error) {
+
+	result := &dbModels.MarginRuleModel{}
+	err := tx.Table(table).
+		Where(table+".product_type IN (?, ?)", productType, dbModels.ProductType_None).
+		Order(table + ".product_type DESC").
+		First(result).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,90 @@
+package matchEventOutboxDao
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const table = "match_event_outbox"
+
+type UpdateModel struct {
+	OutboxStatus *dbModels.OutboxStatus
+	Attempts     *int
+	LastError    *string
+	SentAt       *sql.NullTime
+}
+
+// New a row
+func New(db *gorm.DB, model *dbModels.MatchEventOutboxModel) (int, error) {
+
+	err := db.Table(table).
+		Create(model).Error
+
+	if err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// GetsPendingForUpdate return the oldest pending records and lock them until the transaction ends,
+// other relays wait for the lock so events are sent in order
+func GetsPendingForUpdate(tx *gorm.DB, limit int) ([]dbModels.MatchEventOutboxModel, error) {
+	result := make([]dbModels.MatchEventOutboxModel, 0)
+	err := tx.Table(table).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(table+".outbox_status = ?", dbModels.OutboxStatus_Pending).
+		Order(table + ".id").
+		Limit(limit).
+		Find(&result).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return []dbModels.MatchEventOutboxModel{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Modify update a record
+func Modify(tx *gorm.DB, model *dbModels.MatchEventOutboxModel, update *UpdateModel) error {
+	attrs := map[string]interface{}{}
+	if update.OutboxStatus != nil {
+		attrs["outbox_status"] = *update.OutboxStatus
+	}
+	if update.Attempts != nil {
+		attrs["attempts"] = *update.Attempts
+	}
+	if update.LastError != nil {
+		attrs["last_error"] = *update.LastError
+	}
+	if update.SentAt != nil {
+		attrs["sent_at"] = *update.SentAt
+	}
+
+	err := tx.Table(table).
+		Model(dbModels.MatchEventOutboxModel{}).
+		Where(table+".id = ?", model.ID).
+		Updates(attrs).Error
+
+	return err
+}
+
+// DeleteSent delete at most limit records sent before the time, returns the number deleted
+func DeleteSent(tx *gorm.DB, sentBefore time.Time, limit int) (int64, error) {
+	result := tx.Table(table).
+		Where(table+".outbox_status = ?", dbModels.OutboxStatus_Sent).
+		Where(table+".sent_at < ?", sentBefore).
+		Limit(limit).
+		Delete(&dbModels.MatchEventOutboxModel{})
+
+	return result.RowsAffected, result.Error
+}
@@ -3,6 +3,7 @@ package matchRecordDao
 import (
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/paper-trade-chatbot/be-common/pagination"
 	"github.com/paper-trade-chatbot/be-match/models/dbModels"
@@ -16,14 +17,45 @@ const table = "match_record"
 
 // QueryModel set query condition, used by queryChain()
 type QueryModel struct {
-	ID uint64
+	ID              uint64
+	IDs             []uint64
+	OrderID         uint64
+	OrderIDs        []uint64
+	MemberIDs       []uint64
+	PositionIDs     []uint64
+	ExchangeCode    string
+	ProductCode     string
+	MatchStatus     dbModels.MatchStatus
+	MatchStatuses   []dbModels.MatchStatus
+	TransactionType dbModels.TransactionType
+	LegType         *dbModels.LegType
+	UpdatedBefore   *time.Time
+	CreatedFrom     *time.Time
+	CreatedTo       *time.Time
 }
 
 type UpdateModel struct {
-	MatchStatus *dbModels.MatchStatus
-	PositionID  *sql.NullInt64
-	OpenPrice   *decimal.NullDecimal
-	ClosePrice  *decimal.NullDecimal
+	MatchStatus      *dbModels.MatchStatus
+	SagaStep         *dbModels.SagaStep
+	TransactionID    *sql.NullInt64
+	PositionID       *sql.NullInt64
+	OpenPrice        *decimal.NullDecimal
+	ClosePrice       *decimal.NullDecimal
+	Amount           *decimal.Decimal
+	LegOrderID       *sql.NullInt64
+	ExpireOutcome    *dbModels.ExpireOutcome
+	PricingModel     *dbModels.PricingModel
+	QuoteAsk         *decimal.NullDecimal
+	QuoteBid         *decimal.NullDecimal
+	QuoteTime        *sql.NullTime
+	StaleQuote       *bool
+	RetryCount       *int
+	RetryOutcome     *dbModels.RetryOutcome
+	RetryError       *string
+	Fee              *decimal.Decimal
+	FeeTransactionID *sql.NullInt64
+	Leverage         *decimal.Decimal
+	Margin           *decimal.NullDecimal
 }
 
 // New a row
@@ -65,7 +97,7 @@ func Get(tx *gorm.DB, query *QueryModel) (*dbModels.MatchRecordModel, error) {
 	result := &dbModels.MatchRecordModel{}
 	err := tx.Table(table).
 		Scopes(queryChain(query)).
-		Scan(result).Error
+		First(result).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -76,6 +108,17 @@ func Get(tx *gorm.DB, query *QueryModel) (*dbModels.MatchRecordModel, error) {
 	return result, nil
 }
 
+// GetByOrder return the match record of an order, bracket legs excluded.
+// order_id, transaction_type and leg_type are unique together
+func GetByOrder(tx *gorm.DB, orderID uint64, transactionType dbModels.TransactionType) (*dbModels.MatchRecordModel, error) {
+	legType := dbModels.LegType_None
+	return Get(tx, &QueryModel{
+		OrderID:         orderID,
+		TransactionType: transactionType,
+		LegType:         &legType,
+	})
+}
+
 // Gets return records as raw-data-form
 func Gets(tx *gorm.DB, query *QueryModel) ([]dbModels.MatchRecordModel, error) {
 	result := make([]dbModels.MatchRecordModel, 0)
@@ -101,6 +144,7 @@ func GetsWithPagination(tx *gorm.DB, query *QueryModel, paginate *general.Pagina
 	err := tx.Table(table).
 		Scopes(queryChain(query)).
 		Count(&count).
+		Order(table + ".id DESC").
 		Scopes(paginateChain(paginate)).
 		Scan(&rows).Error
 
@@ -124,6 +168,12 @@ func Modify(tx *gorm.DB, model *dbModels.MatchRecordModel, update *UpdateModel)
 	if update.MatchStatus != nil {
 		attrs["match_status"] = *update.MatchStatus
 	}
+	if update.SagaStep != nil {
+		attrs["saga_step"] = *update.SagaStep
+	}
+	if update.TransactionID != nil {
+		attrs["transaction_id"] = *update.TransactionID
+	}
 	if update.PositionID != nil {
 		attrs["position_id"] = *update.PositionID
 	}
@@ -133,6 +183,51 @@ func Modify(tx *gorm.DB, model *dbModels.MatchRecordModel, update *UpdateModel)
 	if update.ClosePrice != nil {
 		attrs["close_price"] = *update.ClosePrice
 	}
+	if update.Amount != nil {
+		attrs["amount"] = *update.Amount
+	}
+	if update.LegOrderID != nil {
+		attrs["leg_order_id"] = *update.LegOrderID
+	}
+	if update.ExpireOutcome != nil {
+		attrs["expire_outcome"] = *update.ExpireOutcome
+	}
+	if update.PricingModel != nil {
+		attrs["pricing_model"] = *update.PricingModel
+	}
+	if update.QuoteAsk != nil {
+		attrs["quote_ask"] = *update.QuoteAsk
+	}
+	if update.QuoteBid != nil {
+		attrs["quote_bid"] = *update.QuoteBid
+	}
+	if update.QuoteTime != nil {
+		attrs["quote_time"] = *update.QuoteTime
+	}
+	if update.StaleQuote != nil {
+		attrs["stale_quote"] = *update.StaleQuote
+	}
+	if update.RetryCount != nil {
+		attrs["retry_count"] = *update.RetryCount
+	}
+	if update.RetryOutcome != nil {
+		attrs["retry_outcome"] = *update.RetryOutcome
+	}
+	if update.RetryError != nil {
+		attrs["retry_error"] = *update.RetryError
+	}
+	if update.Fee != nil {
+		attrs["fee"] = *update.Fee
+	}
+	if update.FeeTransactionID != nil {
+		attrs["fee_transaction_id"] = *update.FeeTransactionID
+	}
+	if update.Leverage != nil {
+		attrs["leverage"] = *update.Leverage
+	}
+	if update.Margin != nil {
+		attrs["margin"] = *update.Margin
+	}
 
 	err := tx.Table(table).
 		Model(dbModels.MatchRecordModel{}).
@@ -142,10 +237,70 @@ func Modify(tx *gorm.DB, model *dbModels.MatchRecordModel, update *UpdateModel)
 	return err
 }
 
+// RenewIfFailed overwrite a failed record with model so the order can be matched again, returns false if it was not failed
+func RenewIfFailed(tx *gorm.DB, id uint64, model *dbModels.MatchRecordModel) (bool, error) {
+	result := tx.Table(table).
+		Where(table+".id = ?", id).
+		Where(table+".match_status IN ?", []dbModels.MatchStatus{
+			dbModels.MatchStatus_Failed,
+			dbModels.MatchStatus_Cancelled,
+			dbModels.MatchStatus_Rollbacked,
+		}).
+		Select("*").
+		Omit("id", "created_at").
+		Updates(model)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	model.ID = id
+	return true, nil
+}
+
+// CancelLegsByOrder cancel all pending bracket legs of a parent order
+func CancelLegsByOrder(tx *gorm.DB, orderID uint64) error {
+	err := tx.Table(table).
+		Model(dbModels.MatchRecordModel{}).
+		Where(table+".order_id = ?", orderID).
+		Where(table+".leg_type != ?", dbModels.LegType_None).
+		Where(table+".match_status = ?", dbModels.MatchStatus_Pending).
+		Update("match_status", dbModels.MatchStatus_Cancelled).Error
+
+	return err
+}
+
+// CancelLegsByPosition cancel all pending bracket legs of a position
+func CancelLegsByPosition(tx *gorm.DB, positionID uint64) error {
+	err := tx.Table(table).
+		Model(dbModels.MatchRecordModel{}).
+		Where(table+".position_id = ?", positionID).
+		Where(table+".leg_type != ?", dbModels.LegType_None).
+		Where(table+".match_status = ?", dbModels.MatchStatus_Pending).
+		Update("match_status", dbModels.MatchStatus_Cancelled).Error
+
+	return err
+}
+
 func queryChain(query *QueryModel) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
 		return db.
-			Scopes(idEqualScope(query.ID))
+			Scopes(idEqualScope(query.ID)).
+			Scopes(idInScope(query.IDs)).
+			Scopes(orderIDEqualScope(query.OrderID)).
+			Scopes(orderIDInScope(query.OrderIDs)).
+			Scopes(memberIDInScope(query.MemberIDs)).
+			Scopes(positionIDInScope(query.PositionIDs)).
+			Scopes(exchangeCodeEqualScope(query.ExchangeCode)).
+			Scopes(productCodeEqualScope(query.ProductCode)).
+			Scopes(matchStatusEqualScope(query.MatchStatus)).
+			Scopes(matchStatusInScope(query.MatchStatuses)).
+			Scopes(transactionTypeEqualScope(query.TransactionType)).
+			Scopes(legTypeEqualScope(query.LegType)).
+			Scopes(updatedBeforeScope(query.UpdatedBefore)).
+			Scopes(createdFromScope(query.CreatedFrom)).
+			Scopes(createdToScope(query.CreatedTo))
 	}
 }
 
@@ -168,6 +323,132 @@ func idEqualScope(id uint64) func(db *gorm.DB) *gorm.DB {
 	}
 }
 
+func idInScope(ids []uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(ids) > 0 {
+			return db.Where(table+".id IN ?", ids)
+		}
+		return db
+	}
+}
+
+func orderIDEqualScope(orderID uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if orderID != 0 {
+			return db.Where(table+".order_id = ?", orderID)
+		}
+		return db
+	}
+}
+
+func orderIDInScope(orderIDs []uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(orderIDs) > 0 {
+			return db.Where(table+".order_id IN ?", orderIDs)
+		}
+		return db
+	}
+}
+
+func memberIDInScope(memberIDs []uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(memberIDs) > 0 {
+			return db.Where(table+".member_id IN ?", memberIDs)
+		}
+		return db
+	}
+}
+
+func positionIDInScope(positionIDs []uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(positionIDs) > 0 {
+			return db.Where(table+".position_id IN ?", positionIDs)
+		}
+		return db
+	}
+}
+
+func exchangeCodeEqualScope(exchangeCode string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if exchangeCode != "" {
+			return db.Where(table+".exchange_code = ?", exchangeCode)
+		}
+		return db
+	}
+}
+
+func productCodeEqualScope(productCode string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if productCode != "" {
+			return db.Where(table+".product_code = ?", productCode)
+		}
+		return db
+	}
+}
+
+func matchStatusEqualScope(matchStatus dbModels.MatchStatus) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if matchStatus != dbModels.MatchStatus_None {
+			return db.Where(table+".match_status = ?", matchStatus)
+		}
+		return db
+	}
+}
+
+func matchStatusInScope(matchStatuses []dbModels.MatchStatus) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(matchStatuses) > 0 {
+			return db.Where(table+".match_status IN ?", matchStatuses)
+		}
+		return db
+	}
+}
+
+func transactionTypeEqualScope(transactionType dbModels.TransactionType) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if transactionType != dbModels.TransactionType_NONE {
+			return db.Where(table+".transaction_type = ?", transactionType)
+		}
+		return db
+	}
+}
+
+func legTypeEqualScope(legType *dbModels.LegType) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if legType != nil {
+			return db.Where(table+".leg_type = ?", *legType)
+		}
+		return db
+	}
+}
+
+func updatedBeforeScope(updatedBefore *time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if updatedBefore != nil {
+			return db.Where(table+".updated_at < ?", *updatedBefore)
+		}
+		return db
+	}
+}
+
+func createdFromScope(createdFrom *time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if createdFrom != nil {
+			return db.Where(table+".created_at >= ?", *createdFrom)
+		}
+		return db
+	}
+}
+
+func createdToScope(createdTo *time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if createdTo != nil {
+			return db.Where(table+".created_at < ?", *createdTo)
+		}
+		return db
+	}
+}
+
 func limitScope(limit int) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
 		if limit > 0 {
@@ -180,7 +461,7 @@ func limitScope(limit int) func(db *gorm.DB) *gorm.DB {
 func offsetScope(offset int) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
 		if offset > 0 {
-			return db.Limit(offset)
+			return db.Offset(offset)
 		}
 		return db
 	}
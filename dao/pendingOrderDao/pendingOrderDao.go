@@ -0,0 +1,159 @@
+package pendingOrderDao
+
+import (
+	"errors"
+	"time"
+
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+
+	"gorm.io/gorm"
+)
+
+const table = "pending_order"
+
+// QueryModel set query condition, used by queryChain()
+type QueryModel struct {
+	ID              uint64
+	OrderID         uint64
+	PendingStatus   dbModels.PendingStatus
+	TransactionType dbModels.TransactionType
+	ExpireBefore    *time.Time
+}
+
+type UpdateModel struct {
+	PendingStatus *dbModels.PendingStatus
+}
+
+// New a row
+func New(db *gorm.DB, model *dbModels.PendingOrderModel) (int, error) {
+
+	err := db.Table(table).
+		Create(model).Error
+
+	if err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// Get return a record as raw-data-form
+func Get(tx *gorm.DB, query *QueryModel) (*dbModels.PendingOrderModel, error) {
+
+	result := &dbModels.PendingOrderModel{}
+	err := tx.Table(table).
+		Scopes(queryChain(query)).
+		First(result).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Gets return records as raw-data-form
+func Gets(tx *gorm.DB, query *QueryModel) ([]dbModels.PendingOrderModel, error) {
+	result := make([]dbModels.PendingOrderModel, 0)
+	err := tx.Table(table).
+		Scopes(queryChain(query)).
+		Scan(&result).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return []dbModels.PendingOrderModel{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Modify update a record
+func Modify(tx *gorm.DB, model *dbModels.PendingOrderModel, update *UpdateModel) error {
+	attrs := map[string]interface{}{}
+	if update.PendingStatus != nil {
+		attrs["pending_status"] = *update.PendingStatus
+	}
+
+	err := tx.Table(table).
+		Model(dbModels.PendingOrderModel{}).
+		Where(table+".id = ?", model.ID).
+		Updates(attrs).Error
+
+	return err
+}
+
+// ModifyIfPending update a record only if it is still pending, returns false if it was not
+func ModifyIfPending(tx *gorm.DB, model *dbModels.PendingOrderModel, update *UpdateModel) (bool, error) {
+	attrs := map[string]interface{}{}
+	if update.PendingStatus != nil {
+		attrs["pending_status"] = *update.PendingStatus
+	}
+
+	result := tx.Table(table).
+		Model(dbModels.PendingOrderModel{}).
+		Where(table+".id = ?", model.ID).
+		Where(table+".pending_status = ?", dbModels.PendingStatus_Pending).
+		Updates(attrs)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func queryChain(query *QueryModel) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Scopes(idEqualScope(query.ID)).
+			Scopes(orderIDEqualScope(query.OrderID)).
+			Scopes(pendingStatusEqualScope(query.PendingStatus)).
+			Scopes(transactionTypeEqualScope(query.TransactionType)).
+			Scopes(expireBeforeScope(query.ExpireBefore))
+	}
+}
+
+func idEqualScope(id uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if id != 0 {
+			return db.Where(table+".id = ?", id)
+		}
+		return db
+	}
+}
+
+func orderIDEqualScope(orderID uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if orderID != 0 {
+			return db.Where(table+".order_id = ?", orderID)
+		}
+		return db
+	}
+}
+
+func pendingStatusEqualScope(pendingStatus dbModels.PendingStatus) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if pendingStatus != dbModels.PendingStatus_None {
+			return db.Where(table+".pending_status = ?", pendingStatus)
+		}
+		return db
+	}
+}
+
+func transactionTypeEqualScope(transactionType dbModels.TransactionType) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if transactionType != dbModels.TransactionType_NONE {
+			return db.Where(table+".transaction_type = ?", transactionType)
+		}
+		return db
+	}
+}
+
+func expireBeforeScope(expireBefore *time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if expireBefore != nil {
+			return db.Where(table+".expire_at <= ?", *expireBefore)
+		}
+		return db
+	}
+}
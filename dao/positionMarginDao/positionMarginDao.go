@@ -0,0 +1,140 @@
+package positionMarginDao
+
+import (
+	"errors"
+
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/shopspring/decimal"
+
+	"gorm.io/gorm"
+)
+
+const table = "position_margin"
+
+// QueryModel set query condition, used by queryChain()
+type QueryModel struct {
+	ID           uint64
+	PositionID   uint64
+	MemberID     uint64
+	MarginStatus dbModels.MarginStatus
+}
+
+type UpdateModel struct {
+	MarginStatus *dbModels.MarginStatus
+	Amount       *decimal.Decimal
+	Margin       *decimal.Decimal
+}
+
+// New a row
+func New(db *gorm.DB, model *dbModels.PositionMarginModel) (int, error) {
+
+	err := db.Table(table).
+		Create(model).Error
+
+	if err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// Get return a record as raw-data-form
+func Get(tx *gorm.DB, query *QueryModel) (*dbModels.PositionMarginModel, error) {
+
+	result := &dbModels.PositionMarginModel{}
+	err := tx.Table(table).
+		Scopes(queryChain(query)).
+		First(result).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Gets return records as raw-data-form
+func Gets(tx *gorm.DB, query *QueryModel) ([]dbModels.PositionMarginModel, error) {
+	result := make([]dbModels.PositionMarginModel, 0)
+	err := tx.Table(table).
+		Scopes(queryChain(query)).
+		Scan(&result).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return []dbModels.PositionMarginModel{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Modify update a record
+func Modify(tx *gorm.DB, model *dbModels.PositionMarginModel, update *UpdateModel) error {
+	attrs := map[string]interface{}{}
+	if update.MarginStatus != nil {
+		attrs["margin_status"] = *update.MarginStatus
+	}
+	if update.Amount != nil {
+		attrs["amount"] = *update.Amount
+	}
+	if update.Margin != nil {
+		attrs["margin"] = *update.Margin
+	}
+
+	err := tx.Table(table).
+		Model(dbModels.PositionMarginModel{}).
+		Where(table+".id = ?", model.ID).
+		Updates(attrs).Error
+
+	return err
+}
+
+func queryChain(query *QueryModel) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Scopes(idEqualScope(query.ID)).
+			Scopes(positionIDEqualScope(query.PositionID)).
+			Scopes(memberIDEqualScope(query.MemberID)).
+			Scopes(marginStatusEqualScope(query.MarginStatus))
+	}
+}
+
+func idEqualScope(id uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if id != 0 {
+			return db.Where(table+".id = ?", id)
+		}
+		return db
+	}
+}
+
+func positionIDEqualScope(positionID uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if positionID != 0 {
+			return db.Where(table+".position_id = ?", positionID)
+		}
+		return db
+	}
+}
+
+func memberIDEqualScope(memberID uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if memberID != 0 {
+			return db.Where(table+".member_id = ?", memberID)
+		}
+		return db
+	}
+}
+
+func marginStatusEqualScope(marginStatus dbModels.MarginStatus) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if marginStatus != dbModels.MarginStatus_None {
+			return db.Where(table+".margin_status = ?", marginStatus)
+		}
+		return db
+	}
+}
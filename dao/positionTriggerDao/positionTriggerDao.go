@@ -0,0 +1,167 @@
+package positionTriggerDao
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/shopspring/decimal"
+
+	"gorm.io/gorm"
+)
+
+const table = "position_trigger"
+
+// QueryModel set query condition, used by queryChain()
+type QueryModel struct {
+	ID            uint64
+	PositionID    uint64
+	ParentOrderID uint64
+	TriggerStatus dbModels.TriggerStatus
+}
+
+type UpdateModel struct {
+	TriggerStatus  *dbModels.TriggerStatus
+	TriggerPrice   *decimal.Decimal
+	TriggeredPrice *decimal.NullDecimal
+	CloseOrderID   *sql.NullInt64
+	ClosePrice     *decimal.NullDecimal
+	TriggeredAt    *sql.NullTime
+}
+
+// New rows
+func News(db *gorm.DB, m []*dbModels.PositionTriggerModel) (int, error) {
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+
+		err := tx.Table(table).
+			CreateInBatches(m, 3000).Error
+
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return len(m), nil
+}
+
+// Gets return records as raw-data-form
+func Gets(tx *gorm.DB, query *QueryModel) ([]dbModels.PositionTriggerModel, error) {
+	result := make([]dbModels.PositionTriggerModel, 0)
+	err := tx.Table(table).
+		Scopes(queryChain(query)).
+		Scan(&result).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return []dbModels.PositionTriggerModel{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Modify update a record
+func Modify(tx *gorm.DB, model *dbModels.PositionTriggerModel, update *UpdateModel) error {
+	attrs := map[string]interface{}{}
+	if update.TriggerStatus != nil {
+		attrs["trigger_status"] = *update.TriggerStatus
+	}
+	if update.TriggerPrice != nil {
+		attrs["trigger_price"] = *update.TriggerPrice
+	}
+	if update.TriggeredPrice != nil {
+		attrs["triggered_price"] = *update.TriggeredPrice
+	}
+	if update.CloseOrderID != nil {
+		attrs["close_order_id"] = *update.CloseOrderID
+	}
+	if update.ClosePrice != nil {
+		attrs["close_price"] = *update.ClosePrice
+	}
+	if update.TriggeredAt != nil {
+		attrs["triggered_at"] = *update.TriggeredAt
+	}
+
+	err := tx.Table(table).
+		Model(dbModels.PositionTriggerModel{}).
+		Where(table+".id = ?", model.ID).
+		Updates(attrs).Error
+
+	return err
+}
+
+// CancelByPosition cancel all active triggers of a position
+func CancelByPosition(tx *gorm.DB, positionID uint64) error {
+	err := tx.Table(table).
+		Model(dbModels.PositionTriggerModel{}).
+		Where(table+".position_id = ?", positionID).
+		Where(table+".trigger_status = ?", dbModels.TriggerStatus_Active).
+		Update("trigger_status", dbModels.TriggerStatus_Cancelled).Error
+
+	return err
+}
+
+// CancelByParentOrder cancel all active triggers of a parent order
+func CancelByParentOrder(tx *gorm.DB, parentOrderID uint64) error {
+	err := tx.Table(table).
+		Model(dbModels.PositionTriggerModel{}).
+		Where(table+".parent_order_id = ?", parentOrderID).
+		Where(table+".trigger_status = ?", dbModels.TriggerStatus_Active).
+		Update("trigger_status", dbModels.TriggerStatus_Cancelled).Error
+
+	return err
+}
+
+func queryChain(query *QueryModel) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Scopes(idEqualScope(query.ID)).
+			Scopes(positionIDEqualScope(query.PositionID)).
+			Scopes(parentOrderIDEqualScope(query.ParentOrderID)).
+			Scopes(triggerStatusEqualScope(query.TriggerStatus))
+	}
+}
+
+func idEqualScope(id uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if id != 0 {
+			return db.Where(table+".id = ?", id)
+		}
+		return db
+	}
+}
+
+func positionIDEqualScope(positionID uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if positionID != 0 {
+			return db.Where(table+".position_id = ?", positionID)
+		}
+		return db
+	}
+}
+
+func parentOrderIDEqualScope(parentOrderID uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if parentOrderID != 0 {
+			return db.Where(table+".parent_order_id = ?", parentOrderID)
+		}
+		return db
+	}
+}
+
+func triggerStatusEqualScope(triggerStatus dbModels.TriggerStatus) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if triggerStatus != dbModels.TriggerStatus_None {
+			return db.Where(table+".trigger_status = ?", triggerStatus)
+		}
+		return db
+	}
+}
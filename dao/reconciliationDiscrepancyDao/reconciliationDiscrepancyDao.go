@@ -0,0 +1,65 @@
+package reconciliationDiscrepancyDao
+
+import (
+	"errors"
+
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const table = "reconciliation_discrepancy"
+
+type UpdateModel struct {
+	RepairStatus *dbModels.RepairStatus
+	Remark       *string
+}
+
+// NewIfNotExist insert a row unless the same discrepancy was already reported,
+// returns false if the row exists
+func NewIfNotExist(db *gorm.DB, model *dbModels.ReconciliationDiscrepancyModel) (bool, error) {
+
+	result := db.Table(table).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(model)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetByKey return the discrepancy of the unique key
+func GetByKey(tx *gorm.DB, discrepancyType dbModels.DiscrepancyType, orderID, transactionID uint64) (*dbModels.ReconciliationDiscrepancyModel, error) {
+
+	result := &dbModels.ReconciliationDiscrepancyModel{}
+	err := tx.Table(table).
+		Where(table+".discrepancy_type = ?", discrepancyType).
+		Where(table+".order_id = ?", orderID).
+		Where(table+".transaction_id = ?", transactionID).
+		First(result).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Modify update a record
+func Modify(tx *gorm.DB, model *dbModels.ReconciliationDiscrepancyModel, update *UpdateModel) error {
+	attrs := map[string]interface{}{}
+	if update.RepairStatus != nil {
+		attrs["repair_status"] = *update.RepairStatus
+	}
+	if update.Remark != nil {
+		attrs["remark"] = *update.Remark
+	}
+
+	err := tx.Table(table).
+		Model(dbModels.ReconciliationDiscrepancyModel{}).
+		Where(table+".id = ?", model.ID).
+		Updates(attrs).Error
+
+	return err
+}
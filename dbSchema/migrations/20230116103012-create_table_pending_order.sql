@@ -0,0 +1,37 @@
+
+-- +migrate Up
+ALTER TABLE `be-match`.`match_record`
+    ADD COLUMN `order_type` TINYINT(4) NOT NULL DEFAULT 1 COMMENT '訂單類別 1:市價 2:限價' AFTER `trade_type`,
+    ADD COLUMN `limit_price` DECIMAL(19,4) NULL DEFAULT NULL COMMENT '限價' AFTER `order_type`;
+
+CREATE TABLE IF NOT EXISTS `be-match`.`pending_order`
+(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'id',
+    `order_id` BIGINT UNSIGNED NOT NULL COMMENT '訂單id',
+    `match_record_id` BIGINT UNSIGNED NOT NULL COMMENT '撮合紀錄id',
+    `member_id` BIGINT UNSIGNED NOT NULL COMMENT '會員id',
+    `position_id` BIGINT UNSIGNED NULL DEFAULT NULL COMMENT '倉位id, 關倉時才有值',
+    `pending_status` TINYINT(4) NOT NULL COMMENT '掛單狀態 1:掛單中 2:已成交 3:失敗 4:取消',
+    `transaction_type` TINYINT(4) NOT NULL COMMENT '交易類別 1:開倉 2:關倉',
+    `exchange_code` VARCHAR(32) NOT NULL COMMENT '交易所代號',
+    `product_code` VARCHAR(32) NOT NULL COMMENT '產品代號',
+    `trade_type` TINYINT(4) NOT NULL COMMENT '買賣類別 1:買 2:賣',
+    `order_type` TINYINT(4) NOT NULL COMMENT '訂單類別 1:市價 2:限價',
+    `limit_price` DECIMAL(19,4) NOT NULL COMMENT '限價',
+    `open_price` DECIMAL(19,4) NULL DEFAULT NULL COMMENT '開倉價, 關倉時才有值',
+    `amount` DECIMAL(19,4) NOT NULL COMMENT '交易數量',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '創建時間',
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新時間',
+
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX (`order_id`),
+    INDEX (`pending_status`, `transaction_type`)
+) AUTO_INCREMENT=1 CHARSET=`utf8mb4` COLLATE=`utf8mb4_general_ci` COMMENT '掛單';
+
+
+-- +migrate Down
+SET FOREIGN_KEY_CHECKS=0;
+DROP TABLE IF EXISTS `pending_order`;
+ALTER TABLE `be-match`.`match_record`
+    DROP COLUMN `order_type`,
+    DROP COLUMN `limit_price`;
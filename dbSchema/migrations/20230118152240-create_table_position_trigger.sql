@@ -0,0 +1,29 @@
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS `be-match`.`position_trigger`
+(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'id',
+    `position_id` BIGINT UNSIGNED NOT NULL COMMENT '倉位id',
+    `member_id` BIGINT UNSIGNED NOT NULL COMMENT '會員id',
+    `exchange_code` VARCHAR(32) NOT NULL COMMENT '交易所代號',
+    `product_code` VARCHAR(32) NOT NULL COMMENT '產品代號',
+    `trade_type` TINYINT(4) NOT NULL COMMENT '倉位買賣類別 1:買 2:賣',
+    `trigger_type` TINYINT(4) NOT NULL COMMENT '觸發類別 1:停損 2:停利',
+    `trigger_status` TINYINT(4) NOT NULL COMMENT '觸發狀態 1:監控中 2:已觸發 3:取消',
+    `trigger_price` DECIMAL(19,4) NOT NULL COMMENT '觸發價',
+    `triggered_price` DECIMAL(19,4) NULL DEFAULT NULL COMMENT '觸發時的報價',
+    `close_order_id` BIGINT UNSIGNED NULL DEFAULT NULL COMMENT '觸發後的關倉訂單id',
+    `close_price` DECIMAL(19,4) NULL DEFAULT NULL COMMENT '關倉成交價',
+    `triggered_at` TIMESTAMP NULL DEFAULT NULL COMMENT '觸發時間',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '創建時間',
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新時間',
+
+    PRIMARY KEY (`id`),
+    INDEX (`position_id`),
+    INDEX (`trigger_status`)
+) AUTO_INCREMENT=1 CHARSET=`utf8mb4` COLLATE=`utf8mb4_general_ci` COMMENT '倉位停損停利';
+
+
+-- +migrate Down
+SET FOREIGN_KEY_CHECKS=0;
+DROP TABLE IF EXISTS `position_trigger`;
@@ -0,0 +1,13 @@
+
+-- +migrate Up
+ALTER TABLE `be-match`.`position_trigger`
+    MODIFY COLUMN `trigger_type` TINYINT(4) NOT NULL COMMENT '觸發類別 1:停損 2:停利 3:移動停損',
+    MODIFY COLUMN `trigger_price` DECIMAL(19,4) NOT NULL COMMENT '觸發價, 移動停損時為觸發當下的停損價',
+    ADD COLUMN `trail_mode` TINYINT(4) NOT NULL DEFAULT 0 COMMENT '移動停損計算方式 1:固定價差 2:百分比' AFTER `trigger_price`,
+    ADD COLUMN `trail_value` DECIMAL(19,4) NULL DEFAULT NULL COMMENT '移動停損價差或百分比' AFTER `trail_mode`;
+
+
+-- +migrate Down
+ALTER TABLE `be-match`.`position_trigger`
+    DROP COLUMN `trail_mode`,
+    DROP COLUMN `trail_value`;
@@ -0,0 +1,25 @@
+
+-- +migrate Up
+ALTER TABLE `be-match`.`match_record`
+    DROP INDEX `member_id`,
+    ADD INDEX (`member_id`,`exchange_code`, `product_code`,`created_at`),
+    ADD INDEX (`order_id`),
+    ADD COLUMN `leg_type` TINYINT(4) NOT NULL DEFAULT 0 COMMENT '附加單類別 0:非附加單 1:停利 2:停損, 附加單的order_id為母單id' AFTER `limit_price`,
+    ADD COLUMN `leg_order_id` BIGINT UNSIGNED NULL DEFAULT NULL COMMENT '附加單成交時的關倉訂單id' AFTER `leg_type`;
+
+ALTER TABLE `be-match`.`position_trigger`
+    ADD COLUMN `parent_order_id` BIGINT UNSIGNED NULL DEFAULT NULL COMMENT '附加單的母單id, 同母單的觸發互為二擇一' AFTER `trail_value`,
+    ADD COLUMN `match_record_id` BIGINT UNSIGNED NULL DEFAULT NULL COMMENT '附加單的撮合紀錄id' AFTER `parent_order_id`;
+
+
+-- +migrate Down
+ALTER TABLE `be-match`.`position_trigger`
+    DROP COLUMN `parent_order_id`,
+    DROP COLUMN `match_record_id`;
+
+ALTER TABLE `be-match`.`match_record`
+    DROP COLUMN `leg_type`,
+    DROP COLUMN `leg_order_id`,
+    DROP INDEX `order_id`,
+    DROP INDEX `member_id`,
+    ADD UNIQUE INDEX (`member_id`,`exchange_code`, `product_code`,`created_at`);
@@ -0,0 +1,23 @@
+
+-- +migrate Up
+ALTER TABLE `be-match`.`match_record`
+    ADD COLUMN `time_in_force` TINYINT(4) NOT NULL DEFAULT 0 COMMENT '有效期限類別 0,1:GTC 2:IOC 3:FOK 4:DAY 5:GTD' AFTER `leg_order_id`,
+    ADD COLUMN `expire_at` TIMESTAMP NULL DEFAULT NULL COMMENT '到期時間, DAY及GTD才有值' AFTER `time_in_force`,
+    ADD COLUMN `expire_outcome` TINYINT(4) NOT NULL DEFAULT 0 COMMENT '有效期限結果 1:期限內成交 2:未立即成交而取消 3:到期取消' AFTER `expire_at`;
+
+ALTER TABLE `be-match`.`pending_order`
+    ADD COLUMN `time_in_force` TINYINT(4) NOT NULL DEFAULT 0 COMMENT '有效期限類別 0,1:GTC 4:DAY 5:GTD' AFTER `limit_price`,
+    ADD COLUMN `expire_at` TIMESTAMP NULL DEFAULT NULL COMMENT '到期時間' AFTER `time_in_force`,
+    ADD INDEX (`pending_status`, `expire_at`);
+
+
+-- +migrate Down
+ALTER TABLE `be-match`.`pending_order`
+    DROP INDEX `pending_status_2`,
+    DROP COLUMN `time_in_force`,
+    DROP COLUMN `expire_at`;
+
+ALTER TABLE `be-match`.`match_record`
+    DROP COLUMN `time_in_force`,
+    DROP COLUMN `expire_at`,
+    DROP COLUMN `expire_outcome`;
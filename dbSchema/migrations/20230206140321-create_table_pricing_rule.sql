@@ -0,0 +1,33 @@
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS `be-match`.`pricing_rule`
+(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'id',
+    `exchange_code` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '交易所代號, 空字串代表所有交易所',
+    `product_code` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '產品代號, 空字串代表交易所下所有產品',
+    `pricing_model` TINYINT(4) NOT NULL COMMENT '成交價模型 1:對手價 2:中間價 3:固定滑價 4:依數量的市場衝擊',
+    `slippage_bps` DECIMAL(19,4) NOT NULL DEFAULT 0 COMMENT '固定滑價, 單位bps; 市場衝擊時為基本滑價',
+    `impact_bps` DECIMAL(19,4) NOT NULL DEFAULT 0 COMMENT '每參考數量增加的滑價, 單位bps',
+    `reference_amount` DECIMAL(19,4) NULL DEFAULT NULL COMMENT '市場衝擊的參考數量',
+    `max_slippage_bps` DECIMAL(19,4) NULL DEFAULT NULL COMMENT '滑價上限, 單位bps',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '創建時間',
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新時間',
+
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX (`exchange_code`, `product_code`)
+) AUTO_INCREMENT=1 CHARSET=`utf8mb4` COLLATE=`utf8mb4_general_ci` COMMENT '成交價模型設定';
+
+ALTER TABLE `be-match`.`match_record`
+    ADD COLUMN `pricing_model` TINYINT(4) NOT NULL DEFAULT 0 COMMENT '成交價模型 1:對手價 2:中間價 3:固定滑價 4:依數量的市場衝擊' AFTER `amount`,
+    ADD COLUMN `quote_ask` DECIMAL(19,4) NULL DEFAULT NULL COMMENT '成交時的原始ask報價' AFTER `pricing_model`,
+    ADD COLUMN `quote_bid` DECIMAL(19,4) NULL DEFAULT NULL COMMENT '成交時的原始bid報價' AFTER `quote_ask`;
+
+
+-- +migrate Down
+ALTER TABLE `be-match`.`match_record`
+    DROP COLUMN `pricing_model`,
+    DROP COLUMN `quote_ask`,
+    DROP COLUMN `quote_bid`;
+
+SET FOREIGN_KEY_CHECKS=0;
+DROP TABLE IF EXISTS `pricing_rule`;
@@ -0,0 +1,32 @@
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS `be-match`.`fee_rule`
+(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'id',
+    `exchange_code` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '交易所代號, 空字串代表所有交易所',
+    `product_code` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '產品代號, 空字串代表交易所下所有產品',
+    `open_flat_fee` DECIMAL(19,4) NOT NULL DEFAULT 0 COMMENT '開倉固定手續費',
+    `open_rate` DECIMAL(19,8) NOT NULL DEFAULT 0 COMMENT '開倉手續費率, 以成交金額計算, 0.001代表0.1%',
+    `close_flat_fee` DECIMAL(19,4) NOT NULL DEFAULT 0 COMMENT '關倉固定手續費',
+    `close_rate` DECIMAL(19,8) NOT NULL DEFAULT 0 COMMENT '關倉手續費率, 以成交金額計算, 0.001代表0.1%',
+    `min_fee` DECIMAL(19,4) NULL DEFAULT NULL COMMENT '最低手續費',
+    `max_fee` DECIMAL(19,4) NULL DEFAULT NULL COMMENT '最高手續費',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '創建時間',
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新時間',
+
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX (`exchange_code`, `product_code`)
+) AUTO_INCREMENT=1 CHARSET=`utf8mb4` COLLATE=`utf8mb4_general_ci` COMMENT '手續費設定';
+
+ALTER TABLE `be-match`.`match_record`
+    ADD COLUMN `fee` DECIMAL(19,4) NOT NULL DEFAULT 0 COMMENT '手續費' AFTER `quote_bid`,
+    ADD COLUMN `fee_transaction_id` BIGINT UNSIGNED NULL DEFAULT NULL COMMENT '手續費的錢包交易id' AFTER `fee`;
+
+
+-- +migrate Down
+ALTER TABLE `be-match`.`match_record`
+    DROP COLUMN `fee`,
+    DROP COLUMN `fee_transaction_id`;
+
+SET FOREIGN_KEY_CHECKS=0;
+DROP TABLE IF EXISTS `fee_rule`;
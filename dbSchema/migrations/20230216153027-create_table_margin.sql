@@ -0,0 +1,48 @@
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS `be-match`.`margin_rule`
+(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'id',
+    `product_type` TINYINT(4) NOT NULL DEFAULT 0 COMMENT '產品類別 0:所有類別 1:股票 2:加密貨幣 3:外匯 4:期貨',
+    `leverage` DECIMAL(10,2) NOT NULL DEFAULT 1 COMMENT '槓桿倍數',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '創建時間',
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新時間',
+
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX (`product_type`)
+) AUTO_INCREMENT=1 CHARSET=`utf8mb4` COLLATE=`utf8mb4_general_ci` COMMENT '槓桿設定';
+
+CREATE TABLE IF NOT EXISTS `be-match`.`position_margin`
+(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'id',
+    `position_id` BIGINT UNSIGNED NOT NULL COMMENT '倉位id',
+    `member_id` BIGINT UNSIGNED NOT NULL COMMENT '會員id',
+    `exchange_code` VARCHAR(32) NOT NULL COMMENT '交易所代號',
+    `product_code` VARCHAR(32) NOT NULL COMMENT '產品代號',
+    `trade_type` TINYINT(4) NOT NULL COMMENT '交易類型 1:買 2:賣',
+    `margin_status` TINYINT(4) NOT NULL COMMENT '保證金狀態 1:占用中 2:已釋放',
+    `leverage` DECIMAL(10,2) NOT NULL COMMENT '槓桿倍數',
+    `open_price` DECIMAL(19,4) NOT NULL COMMENT '開倉價',
+    `amount` DECIMAL(19,4) NOT NULL COMMENT '尚未平倉的數量',
+    `margin` DECIMAL(19,4) NOT NULL COMMENT '尚未釋放的保證金',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '創建時間',
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新時間',
+
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX (`position_id`),
+    INDEX (`member_id`, `margin_status`)
+) AUTO_INCREMENT=1 CHARSET=`utf8mb4` COLLATE=`utf8mb4_general_ci` COMMENT '倉位保證金帳戶';
+
+ALTER TABLE `be-match`.`match_record`
+    ADD COLUMN `leverage` DECIMAL(10,2) NOT NULL DEFAULT 1 COMMENT '槓桿倍數' AFTER `fee_transaction_id`,
+    ADD COLUMN `margin` DECIMAL(19,4) NULL DEFAULT NULL COMMENT '開倉時為占用的保證金, 關倉時為釋放的保證金' AFTER `leverage`;
+
+
+-- +migrate Down
+ALTER TABLE `be-match`.`match_record`
+    DROP COLUMN `leverage`,
+    DROP COLUMN `margin`;
+
+SET FOREIGN_KEY_CHECKS=0;
+DROP TABLE IF EXISTS `margin_rule`;
+DROP TABLE IF EXISTS `position_margin`;
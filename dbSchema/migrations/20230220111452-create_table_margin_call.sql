@@ -0,0 +1,31 @@
+
+-- +migrate Up
+ALTER TABLE `be-match`.`margin_rule`
+    ADD COLUMN `maintenance_rate` DECIMAL(10,4) NOT NULL DEFAULT 0.25 COMMENT '維持保證金率, 以市值計算, 權益低於此值時追繳' AFTER `leverage`,
+    ADD COLUMN `liquidation_rate` DECIMAL(10,4) NOT NULL DEFAULT 0.1 COMMENT '強制平倉保證金率, 以市值計算, 權益低於此值時強制平倉' AFTER `maintenance_rate`;
+
+CREATE TABLE IF NOT EXISTS `be-match`.`margin_call`
+(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'id',
+    `member_id` BIGINT UNSIGNED NOT NULL COMMENT '會員id',
+    `currency_code` VARCHAR(16) NOT NULL COMMENT '幣別',
+    `margin_call_type` TINYINT(4) NOT NULL COMMENT '類型 1:追繳保證金 2:強制平倉',
+    `equity` DECIMAL(19,4) NOT NULL COMMENT '帳戶權益',
+    `maintenance_margin` DECIMAL(19,4) NOT NULL COMMENT '維持保證金',
+    `liquidation_margin` DECIMAL(19,4) NOT NULL COMMENT '強制平倉保證金',
+    `position_id` BIGINT UNSIGNED NULL DEFAULT NULL COMMENT '強制平倉的倉位id',
+    `match_record_id` BIGINT UNSIGNED NULL DEFAULT NULL COMMENT '強制平倉的撮合紀錄id',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '創建時間',
+
+    PRIMARY KEY (`id`),
+    INDEX (`member_id`, `created_at`)
+) AUTO_INCREMENT=1 CHARSET=`utf8mb4` COLLATE=`utf8mb4_general_ci` COMMENT '追繳保證金及強制平倉事件';
+
+
+-- +migrate Down
+ALTER TABLE `be-match`.`margin_rule`
+    DROP COLUMN `maintenance_rate`,
+    DROP COLUMN `liquidation_rate`;
+
+SET FOREIGN_KEY_CHECKS=0;
+DROP TABLE IF EXISTS `margin_call`;
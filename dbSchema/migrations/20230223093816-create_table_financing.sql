@@ -0,0 +1,46 @@
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS `be-match`.`financing_rule`
+(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'id',
+    `exchange_code` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '交易所代號, 空字串代表所有交易所',
+    `product_code` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '產品代號, 空字串代表交易所下所有產品',
+    `long_rate` DECIMAL(10,6) NOT NULL DEFAULT 0 COMMENT '多倉融資年利率, 以借入金額計算',
+    `short_rate` DECIMAL(10,6) NOT NULL DEFAULT 0 COMMENT '空倉借券年利率, 以市值計算',
+    `days_per_year` INT NOT NULL DEFAULT 365 COMMENT '年利率換算日利率的天數',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '創建時間',
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新時間',
+
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX (`exchange_code`, `product_code`)
+) AUTO_INCREMENT=1 CHARSET=`utf8mb4` COLLATE=`utf8mb4_general_ci` COMMENT '隔夜融資費率設定';
+
+CREATE TABLE IF NOT EXISTS `be-match`.`financing_charge`
+(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'id',
+    `position_id` BIGINT UNSIGNED NOT NULL COMMENT '倉位id',
+    `charge_date` DATE NOT NULL COMMENT '計息日期(UTC)',
+    `member_id` BIGINT UNSIGNED NOT NULL COMMENT '會員id',
+    `exchange_code` VARCHAR(32) NOT NULL COMMENT '交易所代號',
+    `product_code` VARCHAR(32) NOT NULL COMMENT '產品代號',
+    `trade_type` TINYINT(4) NOT NULL COMMENT '交易類型 1:買 2:賣',
+    `financing_status` TINYINT(4) NOT NULL COMMENT '狀態 1:處理中 2:已扣款 3:失敗',
+    `unit_price` DECIMAL(19,4) NOT NULL COMMENT '計息時的市價',
+    `amount` DECIMAL(19,4) NOT NULL COMMENT '計息時的倉位數量',
+    `principal` DECIMAL(19,4) NOT NULL COMMENT '計息本金, 多倉為借入金額, 空倉為市值',
+    `rate` DECIMAL(10,6) NOT NULL COMMENT '年利率',
+    `fee` DECIMAL(19,4) NOT NULL COMMENT '融資費用',
+    `transaction_id` BIGINT UNSIGNED NULL DEFAULT NULL COMMENT '錢包交易id',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '創建時間',
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新時間',
+
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX (`position_id`, `charge_date`),
+    INDEX (`member_id`)
+) AUTO_INCREMENT=1 CHARSET=`utf8mb4` COLLATE=`utf8mb4_general_ci` COMMENT '隔夜融資扣款紀錄';
+
+
+-- +migrate Down
+SET FOREIGN_KEY_CHECKS=0;
+DROP TABLE IF EXISTS `financing_rule`;
+DROP TABLE IF EXISTS `financing_charge`;
@@ -0,0 +1,15 @@
+
+-- +migrate Up
+ALTER TABLE `be-match`.`pricing_rule`
+    ADD COLUMN `max_quote_age` INT UNSIGNED NULL DEFAULT NULL COMMENT '報價最長可接受的時間, 單位秒, 未設定時不檢查' AFTER `max_slippage_bps`;
+
+ALTER TABLE `be-match`.`match_record`
+    ADD COLUMN `quote_time` DATETIME(3) NULL DEFAULT NULL COMMENT '成交時使用的報價時間' AFTER `quote_bid`;
+
+
+-- +migrate Down
+ALTER TABLE `be-match`.`pricing_rule`
+    DROP COLUMN `max_quote_age`;
+
+ALTER TABLE `be-match`.`match_record`
+    DROP COLUMN `quote_time`;
@@ -0,0 +1,33 @@
+
+-- +migrate Up
+-- 訊息重送造成的重複撮合紀錄先移到備份表, 保留已完成的一筆, 沒有時保留最早的一筆
+CREATE TABLE IF NOT EXISTS `be-match`.`match_record_duplicate` LIKE `be-match`.`match_record`;
+
+INSERT INTO `be-match`.`match_record_duplicate`
+SELECT m.* FROM `be-match`.`match_record` m
+WHERE EXISTS (
+    SELECT 1 FROM `be-match`.`match_record` k
+    WHERE k.`order_id` = m.`order_id`
+      AND k.`transaction_type` = m.`transaction_type`
+      AND k.`leg_type` = m.`leg_type`
+      AND ((k.`match_status` = 3) > (m.`match_status` = 3)
+        OR ((k.`match_status` = 3) = (m.`match_status` = 3) AND k.`id` < m.`id`))
+);
+
+DELETE m FROM `be-match`.`match_record` m
+INNER JOIN `be-match`.`match_record_duplicate` d ON d.`id` = m.`id`;
+
+ALTER TABLE `be-match`.`match_record`
+    DROP INDEX `order_id`,
+    ADD UNIQUE INDEX `order_transaction_leg` (`order_id`, `transaction_type`, `leg_type`);
+
+
+-- +migrate Down
+ALTER TABLE `be-match`.`match_record`
+    DROP INDEX `order_transaction_leg`,
+    ADD INDEX (`order_id`);
+
+INSERT INTO `be-match`.`match_record`
+SELECT * FROM `be-match`.`match_record_duplicate`;
+
+DROP TABLE IF EXISTS `be-match`.`match_record_duplicate`;
@@ -0,0 +1,13 @@
+
+-- +migrate Up
+ALTER TABLE `be-match`.`match_record`
+    ADD COLUMN `saga_step` TINYINT NOT NULL DEFAULT 0 COMMENT '撮合進行到的步驟 0:無 1:已建立 2:已扣款 3:已標記撮合成功 4:訂單已完成 5:已回滾' AFTER `match_status`,
+    ADD COLUMN `transaction_id` BIGINT UNSIGNED NULL DEFAULT NULL COMMENT '撮合扣款的錢包交易id' AFTER `quote_time`,
+    ADD INDEX `match_status_updated_at` (`match_status`, `updated_at`);
+
+
+-- +migrate Down
+ALTER TABLE `be-match`.`match_record`
+    DROP INDEX `match_status_updated_at`,
+    DROP COLUMN `saga_step`,
+    DROP COLUMN `transaction_id`;
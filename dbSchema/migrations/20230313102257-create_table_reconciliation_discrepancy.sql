@@ -0,0 +1,34 @@
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS `be-match`.`reconciliation_discrepancy`
+(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'id',
+    `discrepancy_type` TINYINT(4) NOT NULL COMMENT '差異類型 1:查無訂單 2:訂單狀態不符 3:查無錢包交易 4:錢包交易未成功 5:未回滾扣款 6:無撮合紀錄的錢包交易',
+    `order_id` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '訂單id, 無法對應訂單時為0',
+    `transaction_id` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '錢包交易id, 與交易無關時為0',
+    `match_record_id` BIGINT UNSIGNED NULL DEFAULT NULL COMMENT '撮合紀錄id',
+    `member_id` BIGINT UNSIGNED NOT NULL COMMENT '會員id',
+    `match_status` TINYINT(4) NULL DEFAULT NULL COMMENT '撮合紀錄狀態',
+    `order_status` TINYINT(4) NULL DEFAULT NULL COMMENT '訂單服務的訂單狀態',
+    `transaction_status` TINYINT(4) NULL DEFAULT NULL COMMENT '錢包服務的交易狀態',
+    `repair_status` TINYINT(4) NOT NULL COMMENT '修復狀態 1:待人工處理 2:已自動修復 3:自動修復失敗',
+    `remark` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '備註',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '創建時間',
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新時間',
+
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX (`discrepancy_type`, `order_id`, `transaction_id`),
+    INDEX (`repair_status`),
+    INDEX (`member_id`)
+) AUTO_INCREMENT=1 CHARSET=`utf8mb4` COLLATE=`utf8mb4_general_ci` COMMENT '撮合紀錄與錢包交易及訂單的對帳差異';
+
+ALTER TABLE `be-match`.`match_record`
+    ADD INDEX `created_at` (`created_at`);
+
+
+-- +migrate Down
+SET FOREIGN_KEY_CHECKS=0;
+DROP TABLE IF EXISTS `reconciliation_discrepancy`;
+
+ALTER TABLE `be-match`.`match_record`
+    DROP INDEX `created_at`;
@@ -0,0 +1,13 @@
+
+-- +migrate Up
+ALTER TABLE `be-match`.`match_record`
+    ADD COLUMN `retry_count` INT UNSIGNED NOT NULL DEFAULT 0 COMMENT '最後一次撮合的嘗試次數' AFTER `quote_time`,
+    ADD COLUMN `retry_outcome` TINYINT NOT NULL DEFAULT 0 COMMENT '重試結果 0:無 1:成功 2:次數用完 3:超過時限 4:錯誤不可重試 5:中止' AFTER `retry_count`,
+    ADD COLUMN `retry_error` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '最後一次嘗試的錯誤訊息' AFTER `retry_outcome`;
+
+
+-- +migrate Down
+ALTER TABLE `be-match`.`match_record`
+    DROP COLUMN `retry_count`,
+    DROP COLUMN `retry_outcome`,
+    DROP COLUMN `retry_error`;
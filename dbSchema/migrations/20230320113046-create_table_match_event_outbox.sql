@@ -0,0 +1,28 @@
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS `be-match`.`match_event_outbox`
+(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'id',
+    `event_id` CHAR(36) NOT NULL COMMENT '事件id, 消費端以此去除重複',
+    `event_type` TINYINT(4) NOT NULL COMMENT '事件類型 1:開始撮合 2:撮合成交 3:撮合失敗 4:回滾',
+    `order_id` BIGINT UNSIGNED NOT NULL COMMENT '訂單id',
+    `match_record_id` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '撮合紀錄id, 撮合紀錄建立前失敗時為0',
+    `payload` TEXT NOT NULL COMMENT '事件內容json',
+    `outbox_status` TINYINT(4) NOT NULL COMMENT '狀態 1:待送出 2:已送出',
+    `attempts` INT UNSIGNED NOT NULL DEFAULT 0 COMMENT '送出次數',
+    `last_error` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '最後一次送出失敗的錯誤訊息',
+    `sent_at` TIMESTAMP NULL DEFAULT NULL COMMENT '送出時間',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '創建時間',
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新時間',
+
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX (`event_id`),
+    INDEX (`outbox_status`, `id`),
+    INDEX (`order_id`),
+    INDEX (`sent_at`)
+) AUTO_INCREMENT=1 CHARSET=`utf8mb4` COLLATE=`utf8mb4_general_ci` COMMENT '撮合事件的outbox';
+
+
+-- +migrate Down
+SET FOREIGN_KEY_CHECKS=0;
+DROP TABLE IF EXISTS `match_event_outbox`;
@@ -0,0 +1,10 @@
+
+-- +migrate Up
+ALTER TABLE `be-match`.`pending_order`
+    MODIFY COLUMN `pending_status` TINYINT(4) NOT NULL COMMENT '掛單狀態 1:掛單中 2:已成交 3:失敗 4:取消 5:撮合中';
+
+
+-- +migrate Down
+UPDATE `be-match`.`pending_order` SET `pending_status` = 1 WHERE `pending_status` = 5;
+ALTER TABLE `be-match`.`pending_order`
+    MODIFY COLUMN `pending_status` TINYINT(4) NOT NULL COMMENT '掛單狀態 1:掛單中 2:已成交 3:失敗 4:取消';
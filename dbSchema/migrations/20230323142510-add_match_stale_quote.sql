@@ -0,0 +1,9 @@
+
+-- +migrate Up
+ALTER TABLE `be-match`.`match_record`
+    ADD COLUMN `stale_quote` TINYINT(1) NOT NULL DEFAULT 0 COMMENT '最後一次取得的報價是否過期' AFTER `quote_time`;
+
+
+-- +migrate Down
+ALTER TABLE `be-match`.`match_record`
+    DROP COLUMN `stale_quote`;
@@ -0,0 +1,10 @@
+
+-- +migrate Up
+-- 到期掃描用的索引原本未命名, 改為明確的名稱
+ALTER TABLE `be-match`.`pending_order`
+    RENAME INDEX `pending_status_2` TO `idx_pending_status_expire_at`;
+
+
+-- +migrate Down
+ALTER TABLE `be-match`.`pending_order`
+    RENAME INDEX `idx_pending_status_expire_at` TO `pending_status_2`;
@@ -0,0 +1,234 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/paper-trade-chatbot/be-common/cache"
+	"github.com/paper-trade-chatbot/be-common/database"
+	"github.com/paper-trade-chatbot/be-common/global"
+	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-match/pubsub"
+	"github.com/paper-trade-chatbot/be-match/pubsub/deadLetter"
+	"github.com/paper-trade-chatbot/be-match/service"
+)
+
+const (
+	probeInterval = 10 * time.Second
+	probeTimeout  = 3 * time.Second
+)
+
+type Status string
+
+const (
+	Status_Up   Status = "up"
+	Status_Down Status = "down"
+)
+
+// check 一個相依服務的檢查
+// liveness為true時代表連線中斷後不會自行恢復, 失敗時需重啟服務
+type check struct {
+	name     string
+	liveness bool
+	probe    func(ctx context.Context) error
+}
+
+// Result 相依服務最近一次的檢查結果
+type Result struct {
+	Status    Status    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	LatencyMs int64     `json:"latencyMs"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+var (
+	checks      = []check{}
+	results     = map[string]*Result{}
+	resultsLock sync.RWMutex
+	statusLock  sync.RWMutex
+	cancelProbe context.CancelFunc
+	probeDone   chan struct{}
+)
+
+func registerChecks() {
+	checks = []check{}
+
+	for _, name := range service.ConnNames() {
+		name := name
+		checks = append(checks, check{
+			name: "grpc:" + name,
+			probe: func(ctx context.Context) error {
+				return service.Probe(ctx, name)
+			},
+		})
+	}
+
+	checks = append(checks, check{
+		name: "mysql",
+		probe: func(ctx context.Context) error {
+			db, err := database.GetDB().DB()
+			if err != nil {
+				return err
+			}
+			return db.PingContext(ctx)
+		},
+	})
+
+	checks = append(checks, check{
+		name: "redis",
+		probe: func(ctx context.Context) error {
+			r, err := cache.GetRedis()
+			if err != nil {
+				return err
+			}
+			return r.Ping(ctx).Err()
+		},
+	})
+
+	for queue, probe := range pubsub.Probes() {
+		probe := probe
+		checks = append(checks, check{
+			name:     "rabbitmq:" + queue,
+			liveness: true,
+			probe: func(ctx context.Context) error {
+				return probe()
+			},
+		})
+	}
+
+	checks = append(checks, check{
+		name:     "rabbitmq:deadLetter",
+		liveness: true,
+		probe: func(ctx context.Context) error {
+			return deadLetter.Probe()
+		},
+	})
+}
+
+// Start 檢查一次所有相依服務後, 定期檢查並依結果設定readiness及liveness
+func Start(ctx context.Context) {
+	probeAll(ctx)
+
+	ctxCancel, cancel := context.WithCancel(ctx)
+	cancelProbe = cancel
+	probeDone = make(chan struct{})
+
+	go func() {
+		defer close(probeDone)
+
+		ticker := time.NewTicker(probeInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctxCancel.Done():
+				return
+			case <-ticker.C:
+				probeAll(ctxCancel)
+			}
+		}
+	}()
+}
+
+// Finalize 停止定期檢查, 關閉服務時先停止, 避免停止消費後被判定為需要重啟
+func Finalize(ctx context.Context) {
+	if cancelProbe == nil {
+		return
+	}
+	cancelProbe()
+	<-probeDone
+	cancelProbe = nil
+}
+
+func probeAll(ctx context.Context) {
+	wg := sync.WaitGroup{}
+	latest := make([]*Result, len(checks))
+	for i := range checks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			latest[i] = probe(ctx, &checks[i])
+		}(i)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	ready, alive := true, true
+	resultsLock.Lock()
+	for i, c := range checks {
+		previous, ok := results[c.name]
+		if latest[i].Status == Status_Down {
+			ready = false
+			if c.liveness {
+				alive = false
+			}
+			if !ok || previous.Status != Status_Down {
+				logging.Warn(ctx, "[Health] %s is down: %s", c.name, latest[i].Error)
+			}
+		} else if ok && previous.Status == Status_Down {
+			logging.Info(ctx, "[Health] %s is up.", c.name)
+		}
+		results[c.name] = latest[i]
+	}
+	resultsLock.Unlock()
+
+	statusLock.Lock()
+	global.Ready = ready
+	global.Alive = alive
+	statusLock.Unlock()
+}
+
+func probe(ctx context.Context, c *check) *Result {
+	ctxTimeout, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.probe(ctxTimeout)
+	result := &Result{
+		Status:    Status_Up,
+		LatencyMs: time.Since(start).Milliseconds(),
+		CheckedAt: start,
+	}
+	if err != nil {
+		result.Status = Status_Down
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Results 回傳各相依服務最近一次的檢查結果
+func Results() map[string]Result {
+	resultsLock.RLock()
+	defer resultsLock.RUnlock()
+
+	r := map[string]Result{}
+	for name, result := range results {
+		r[name] = *result
+	}
+	return r
+}
+
+// SetReady 更新就緒狀態, be-match內對global.Ready及global.Alive的存取一律經由statusLock
+func SetReady(ready bool) {
+	statusLock.Lock()
+	defer statusLock.Unlock()
+	global.Ready = ready
+}
+
+// SetAlive 更新存活狀態
+func SetAlive(alive bool) {
+	statusLock.Lock()
+	defer statusLock.Unlock()
+	global.Alive = alive
+}
+
+// State 回傳目前的就緒及存活狀態
+func State() (ready, alive bool) {
+	statusLock.RLock()
+	defer statusLock.RUnlock()
+	return global.Ready, global.Alive
+}
@@ -0,0 +1,66 @@
+package health
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/paper-trade-chatbot/be-common/api"
+	"github.com/paper-trade-chatbot/be-match/service"
+)
+
+// Initialize 在be-common的router上註冊健康檢查的endpoint
+// 需在service及pubsub初始化之後呼叫, 才能取得要檢查的相依服務
+func Initialize(ctx context.Context) {
+	registerChecks()
+
+	root := api.GetRoot()
+	root.GET("health", Dependencies)
+	root.GET("health/dependencies/:name", Dependency)
+	root.GET("health/breakers", Breakers)
+}
+
+// Dependencies 回傳各相依服務的檢查結果, 未就緒時回傳503
+func Dependencies(ctx *gin.Context) {
+	ready, alive := State()
+	code := http.StatusOK
+	if !ready {
+		code = http.StatusServiceUnavailable
+	}
+
+	ctx.JSON(code, gin.H{
+		"ready":        ready,
+		"alive":        alive,
+		"dependencies": Results(),
+	})
+}
+
+// Dependency 回傳單一相依服務的檢查結果, 異常時回傳503
+func Dependency(ctx *gin.Context) {
+	result, ok := Results()[ctx.Param("name")]
+	if !ok {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error": "no such dependency",
+		})
+		return
+	}
+
+	code := http.StatusOK
+	if result.Status != Status_Up {
+		code = http.StatusServiceUnavailable
+	}
+	ctx.JSON(code, result)
+}
+
+// Breakers 回傳各下游服務斷路器的狀態
+func Breakers(ctx *gin.Context) {
+	states := map[string]string{}
+	for name, state := range service.BreakerStates() {
+		states[name] = state.String()
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"available": service.Available(),
+		"breakers":  states,
+	})
+}
@@ -0,0 +1,60 @@
+package closeAmount
+
+import (
+	"context"
+	"os"
+
+	common "github.com/paper-trade-chatbot/be-common"
+	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-match/lib/matchError"
+	"github.com/paper-trade-chatbot/be-match/service"
+	"github.com/paper-trade-chatbot/be-proto/position"
+	"github.com/shopspring/decimal"
+)
+
+type Policy string
+
+const (
+	Policy_Reject Policy = "reject" // 關倉數量超過倉位數量時拒絕
+	Policy_Clip   Policy = "clip"   // 關倉數量超過倉位數量時以倉位數量關倉
+)
+
+// GetPolicy 由CLOSE_AMOUNT_POLICY設定, 未設定或設定錯誤時拒絕
+func GetPolicy() Policy {
+	if policy, ok := os.LookupEnv("CLOSE_AMOUNT_POLICY"); ok && Policy(policy) == Policy_Clip {
+		return Policy_Clip
+	}
+	return Policy_Reject
+}
+
+// Validate 以position服務目前的倉位數量檢查關倉數量, 回傳實際關倉數量
+// 呼叫端需持有倉位的鎖, 避免同一倉位的關倉同時通過檢查
+func Validate(ctx context.Context, positionID uint64, closeAmount decimal.Decimal) (decimal.Decimal, error) {
+	if !closeAmount.IsPositive() {
+		return decimal.Zero, common.ErrInvalidParam
+	}
+
+	positionRes, err := service.Impl.PositionIntf.GetPositions(ctx, &position.GetPositionsReq{
+		Id: []uint64{positionID},
+	})
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if len(positionRes.Positions) == 0 || positionRes.Positions[0].Status != position.PositionStatus_PositionStatus_Open {
+		return decimal.Zero, common.ErrNoSuchPosition
+	}
+
+	openAmount, err := decimal.NewFromString(positionRes.Positions[0].Amount)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if closeAmount.LessThanOrEqual(openAmount) {
+		return closeAmount, nil
+	}
+
+	if GetPolicy() == Policy_Clip && openAmount.IsPositive() {
+		logging.Warn(ctx, "[CloseAmount] close amount [%s] of position [%d] clipped to [%s].", closeAmount, positionID, openAmount)
+		return openAmount, nil
+	}
+	return decimal.Zero, matchError.ErrCloseAmountExceeded
+}
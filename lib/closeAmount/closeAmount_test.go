@@ -0,0 +1,109 @@
+package closeAmount
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	common "github.com/paper-trade-chatbot/be-common"
+	"github.com/paper-trade-chatbot/be-match/lib/matchError"
+	"github.com/paper-trade-chatbot/be-match/service"
+	servicePosition "github.com/paper-trade-chatbot/be-match/service/position"
+	"github.com/paper-trade-chatbot/be-proto/position"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+// fakePosition 只實作GetPositions, 回傳固定的倉位或錯誤
+type fakePosition struct {
+	servicePosition.PositionIntf
+	positions []*position.Position
+	err       error
+}
+
+func (f *fakePosition) GetPositions(ctx context.Context, in *position.GetPositionsReq) (*position.GetPositionsRes, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &position.GetPositionsRes{Positions: f.positions}, nil
+}
+
+func TestGetPolicy(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		set   bool
+		want  Policy
+	}{
+		{"unset", "", false, Policy_Reject},
+		{"reject", "reject", true, Policy_Reject},
+		{"clip", "clip", true, Policy_Clip},
+		{"invalid", "CLIP", true, Policy_Reject},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.set {
+				t.Setenv("CLOSE_AMOUNT_POLICY", tt.value)
+			}
+			if got := GetPolicy(); got != tt.want {
+				t.Errorf("GetPolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	open := func(amount string) []*position.Position {
+		return []*position.Position{{
+			Id:     1,
+			Status: position.PositionStatus_PositionStatus_Open,
+			Amount: amount,
+		}}
+	}
+	unavailable := status.Error(codes.Unavailable, "down")
+
+	tests := []struct {
+		name        string
+		policy      Policy
+		position    *fakePosition
+		closeAmount decimal.Decimal
+		want        decimal.Decimal
+		wantErr     error
+	}{
+		{"zero amount", Policy_Reject, &fakePosition{positions: open("10")}, d("0"), d("0"), common.ErrInvalidParam},
+		{"negative amount", Policy_Reject, &fakePosition{positions: open("10")}, d("-1"), d("0"), common.ErrInvalidParam},
+		{"service error", Policy_Reject, &fakePosition{err: unavailable}, d("1"), d("0"), unavailable},
+		{"no position", Policy_Reject, &fakePosition{}, d("1"), d("0"), common.ErrNoSuchPosition},
+		{"closed position", Policy_Reject, &fakePosition{positions: []*position.Position{{
+			Id:     1,
+			Status: position.PositionStatus_PositionStatus_Close,
+			Amount: "10",
+		}}}, d("1"), d("0"), common.ErrNoSuchPosition},
+		{"partial close", Policy_Reject, &fakePosition{positions: open("10")}, d("4"), d("4"), nil},
+		{"full close", Policy_Reject, &fakePosition{positions: open("10")}, d("10"), d("10"), nil},
+		{"exceeded rejected", Policy_Reject, &fakePosition{positions: open("10")}, d("11"), d("0"), matchError.ErrCloseAmountExceeded},
+		{"exceeded clipped", Policy_Clip, &fakePosition{positions: open("10")}, d("11"), d("10"), nil},
+		{"empty position not clipped", Policy_Clip, &fakePosition{positions: open("0")}, d("1"), d("0"), matchError.ErrCloseAmountExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CLOSE_AMOUNT_POLICY", string(tt.policy))
+			service.Impl.PositionIntf = tt.position
+
+			got, err := Validate(context.Background(), 1, tt.closeAmount)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
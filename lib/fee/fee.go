@@ -0,0 +1,39 @@
+package fee
+
+import (
+	"github.com/paper-trade-chatbot/be-match/dao/feeRuleDao"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// GetFee 依產品的手續費設定計算手續費, 沒有設定時不收手續費
+func GetFee(db *gorm.DB, exchangeCode, productCode string, transactionType dbModels.TransactionType, notional decimal.Decimal) (decimal.Decimal, error) {
+	rule, err := feeRuleDao.GetByProduct(db, exchangeCode, productCode)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return Calculate(rule, transactionType, notional), nil
+}
+
+// Calculate 手續費 = 固定手續費 + 成交金額 * 費率, 並限制在最低及最高手續費之間
+func Calculate(rule *dbModels.FeeRuleModel, transactionType dbModels.TransactionType, notional decimal.Decimal) decimal.Decimal {
+	if rule == nil {
+		return decimal.Zero
+	}
+
+	flatFee, rate := rule.OpenFlatFee, rule.OpenRate
+	if transactionType == dbModels.TransactionType_ClosePosition ||
+		transactionType == dbModels.TransactionType_Liquidation {
+		flatFee, rate = rule.CloseFlatFee, rule.CloseRate
+	}
+
+	fee := flatFee.Add(notional.Abs().Mul(rate))
+	if rule.MinFee.Valid && fee.LessThan(rule.MinFee.Decimal) {
+		fee = rule.MinFee.Decimal
+	}
+	if rule.MaxFee.Valid && fee.GreaterThan(rule.MaxFee.Decimal) {
+		fee = rule.MaxFee.Decimal
+	}
+	return fee.Round(4)
+}
@@ -0,0 +1,53 @@
+package fee
+
+import (
+	"testing"
+
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/shopspring/decimal"
+)
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func TestCalculate(t *testing.T) {
+	rule := &dbModels.FeeRuleModel{
+		OpenFlatFee:  d("1"),
+		OpenRate:     d("0.001"),
+		CloseFlatFee: d("2"),
+		CloseRate:    d("0.002"),
+	}
+	bounded := &dbModels.FeeRuleModel{
+		OpenFlatFee: d("1"),
+		OpenRate:    d("0.001"),
+		MinFee:      decimal.NewNullDecimal(d("5")),
+		MaxFee:      decimal.NewNullDecimal(d("50")),
+	}
+
+	tests := []struct {
+		name            string
+		rule            *dbModels.FeeRuleModel
+		transactionType dbModels.TransactionType
+		notional        decimal.Decimal
+		want            decimal.Decimal
+	}{
+		{"no rule", nil, dbModels.TransactionType_OpenPosition, d("10000"), d("0")},
+		{"open", rule, dbModels.TransactionType_OpenPosition, d("10000"), d("11")},
+		{"close", rule, dbModels.TransactionType_ClosePosition, d("10000"), d("22")},
+		{"liquidation uses close rate", rule, dbModels.TransactionType_Liquidation, d("10000"), d("22")},
+		{"negative notional", rule, dbModels.TransactionType_OpenPosition, d("-10000"), d("11")},
+		{"rounded to 4 places", rule, dbModels.TransactionType_OpenPosition, d("0.12345"), d("1.0001")},
+		{"min fee", bounded, dbModels.TransactionType_OpenPosition, d("100"), d("5")},
+		{"within bounds", bounded, dbModels.TransactionType_OpenPosition, d("10000"), d("11")},
+		{"max fee", bounded, dbModels.TransactionType_OpenPosition, d("1000000"), d("50")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Calculate(tt.rule, tt.transactionType, tt.notional); !got.Equal(tt.want) {
+				t.Errorf("Calculate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package financing
+
+import (
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/shopspring/decimal"
+)
+
+// Principal 計息本金
+// 多倉為市值扣除保證金後向券商借入的金額, 未開槓桿時為0; 空倉為借券的市值
+func Principal(positionMargin *dbModels.PositionMarginModel, unitPrice decimal.Decimal) decimal.Decimal {
+	notional := unitPrice.Mul(positionMargin.Amount)
+	if positionMargin.TradeType == dbModels.TradeType_Sell {
+		return notional
+	}
+	borrowed := notional.Sub(positionMargin.Margin)
+	if borrowed.IsNegative() {
+		return decimal.Zero
+	}
+	return borrowed
+}
+
+// Rate 依倉位方向取得年利率
+func Rate(rule *dbModels.FinancingRuleModel, tradeType dbModels.TradeType) decimal.Decimal {
+	if tradeType == dbModels.TradeType_Sell {
+		return rule.ShortRate
+	}
+	return rule.LongRate
+}
+
+// DailyFee 一日的融資費用 = 本金 * 年利率 / 一年天數
+func DailyFee(rule *dbModels.FinancingRuleModel, principal, rate decimal.Decimal) decimal.Decimal {
+	daysPerYear := rule.DaysPerYear
+	if daysPerYear <= 0 {
+		daysPerYear = 365
+	}
+	return principal.Mul(rate).Div(decimal.NewFromInt(daysPerYear)).Round(4)
+}
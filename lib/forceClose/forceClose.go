@@ -0,0 +1,140 @@
+package forceClose
+
+import (
+	"context"
+
+	common "github.com/paper-trade-chatbot/be-common"
+	"github.com/paper-trade-chatbot/be-common/database"
+	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-match/dao/matchRecordDao"
+	"github.com/paper-trade-chatbot/be-match/lib/inflight"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/paper-trade-chatbot/be-match/models/mqModels"
+	"github.com/paper-trade-chatbot/be-match/pubsub/matchClosePosition"
+	"github.com/paper-trade-chatbot/be-match/service"
+	"github.com/paper-trade-chatbot/be-proto/order"
+	"github.com/paper-trade-chatbot/be-proto/position"
+	"github.com/paper-trade-chatbot/be-pubsub/order/closePosition/rabbitmq"
+	"github.com/shopspring/decimal"
+)
+
+// ClosePosition 由be-match主動將整個倉位平倉, 流程與使用者下關倉單相同:
+// 先將倉位轉為待關倉, 向order建立關倉單, 再交給MatchClosePosition撮合
+// 撮合紀錄的TransactionType由transactionType決定, 用來區分停損停利與強制平倉
+// 倉位已關閉時回傳ErrNoSuchPosition, 倉位正在關倉中時回傳ErrProcessStateNotOpen, 關閉服務中回傳ErrDraining
+func ClosePosition(ctx context.Context, positionID uint64, transactionType dbModels.TransactionType) (*dbModels.MatchRecordModel, error) {
+	return closePosition(ctx, positionID, decimal.NullDecimal{}, decimal.NullDecimal{}, transactionType)
+}
+
+// ClosePositionAtLimit 以限價IOC平倉amount數量, 超過倉位數量時平整個倉位
+// 報價未達限價時取消, 回傳ErrOrderNotFilledImmediately
+func ClosePositionAtLimit(ctx context.Context, positionID uint64, amount, limitPrice decimal.Decimal, transactionType dbModels.TransactionType) (*dbModels.MatchRecordModel, error) {
+	return closePosition(ctx, positionID, decimal.NewNullDecimal(amount), decimal.NewNullDecimal(limitPrice), transactionType)
+}
+
+func closePosition(ctx context.Context, positionID uint64, amount, limitPrice decimal.NullDecimal, transactionType dbModels.TransactionType) (*dbModels.MatchRecordModel, error) {
+
+	// 建立關倉單後必須撮合完成, 關閉服務中不再開始
+	if !inflight.Enter() {
+		return nil, inflight.ErrDraining
+	}
+	defer inflight.Leave()
+
+	positionRes, err := service.Impl.PositionIntf.GetPositions(ctx, &position.GetPositionsReq{
+		Id: []uint64{positionID},
+	})
+	if err != nil {
+		logging.Error(ctx, "[ClosePosition] failed to get position [%d]: %v", positionID, err)
+		return nil, err
+	}
+	if len(positionRes.Positions) == 0 {
+		logging.Error(ctx, "[ClosePosition] failed to get position [%d]: %v", positionID, common.ErrNoSuchPosition)
+		return nil, common.ErrNoSuchPosition
+	}
+	p := positionRes.Positions[0]
+	if p.Status != position.PositionStatus_PositionStatus_Open {
+		return nil, common.ErrNoSuchPosition
+	}
+	if p.ProcessState != position.ProcessState_ProcessState_Open {
+		return nil, common.ErrProcessStateNotOpen
+	}
+
+	openPrice, err := decimal.NewFromString(p.UnitPrice)
+	if err != nil {
+		logging.Error(ctx, "[ClosePosition] NewFromString failed: %v", err)
+		return nil, err
+	}
+	closeAmount, err := decimal.NewFromString(p.Amount)
+	if err != nil {
+		logging.Error(ctx, "[ClosePosition] NewFromString failed: %v", err)
+		return nil, err
+	}
+	if amount.Valid && amount.Decimal.IsPositive() && amount.Decimal.LessThan(closeAmount) {
+		closeAmount = amount.Decimal
+	}
+
+	pendingRes, err := service.Impl.PositionIntf.PendingToClosePosition(ctx, &position.PendingToClosePositionReq{
+		Id:          positionID,
+		CloseAmount: closeAmount.String(),
+	})
+	if err != nil {
+		logging.Error(ctx, "[ClosePosition] PendingToClosePosition [%d] failed: %v", positionID, err)
+		return nil, err
+	}
+	if !pendingRes.PreemptSuccess {
+		logging.Warn(ctx, "[ClosePosition] position [%d] is being closed by others.", positionID)
+		return nil, common.ErrProcessStateNotOpen
+	}
+
+	orderRes, err := service.Impl.OrderIntf.StartClosePositionOrder(ctx, &order.StartClosePositionOrderReq{
+		Id:     positionID,
+		Amount: closeAmount.String(),
+	})
+	if err != nil {
+		logging.Error(ctx, "[ClosePosition] StartClosePositionOrder [%d] failed: %v", positionID, err)
+		if _, err := service.Impl.PositionIntf.StopPendingPosition(ctx, &position.StopPendingPositionReq{
+			Id: positionID,
+		}); err != nil {
+			logging.Error(ctx, "[ClosePosition] StopPendingPosition failed: %v", err)
+		}
+		return nil, err
+	}
+
+	orderType := dbModels.OrderType_Market
+	tif := dbModels.TimeInForce_None
+	if limitPrice.Valid {
+		orderType = dbModels.OrderType_Limit
+		tif = dbModels.TimeInForce_IOC
+	}
+
+	matchErr := matchClosePosition.MatchClosePosition(ctx, &mqModels.ClosePositionModel{
+		ClosePositionModel: rabbitmq.ClosePositionModel{
+			ID:           orderRes.Id,
+			MemberID:     p.MemberID,
+			PositionID:   positionID,
+			ExchangeCode: p.ExchangeCode,
+			ProductCode:  p.ProductCode,
+			TradeType:    rabbitmq.TradeType(p.TradeType),
+			OpenPrice:    openPrice,
+			CloseAmount:  closeAmount,
+		},
+		OrderType:       orderType,
+		LimitPrice:      limitPrice,
+		TimeInForce:     tif,
+		TransactionType: transactionType,
+	})
+
+	legType := dbModels.LegType_None
+	matchRecord, err := matchRecordDao.Get(database.GetDB(), &matchRecordDao.QueryModel{
+		OrderID: orderRes.Id,
+		LegType: &legType,
+	})
+	if err != nil {
+		logging.Error(ctx, "[ClosePosition] failed to get matchRecord of order [%d]: %v", orderRes.Id, err)
+	}
+	if matchRecord == nil {
+		matchRecord = &dbModels.MatchRecordModel{OrderID: orderRes.Id}
+	}
+
+	return matchRecord, matchErr
+}
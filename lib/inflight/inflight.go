@@ -0,0 +1,72 @@
+package inflight
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// pollInterval 等待撮合完成時的檢查間隔
+const pollInterval = 100 * time.Millisecond
+
+var ErrDraining = errors.New("service is shutting down")
+
+var (
+	lock     sync.Mutex
+	running  int
+	draining bool
+)
+
+// Enter 開始一筆撮合, 關閉服務中回傳false, 呼叫端不應開始撮合
+// 回傳true時撮合結束後需呼叫Leave
+func Enter() bool {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if draining {
+		return false
+	}
+	running++
+	return true
+}
+
+// Leave 結束一筆撮合
+func Leave() {
+	lock.Lock()
+	defer lock.Unlock()
+	running--
+}
+
+// Draining 是否正在關閉服務
+func Draining() bool {
+	lock.Lock()
+	defer lock.Unlock()
+	return draining
+}
+
+// Running 進行中的撮合數量
+func Running() int {
+	lock.Lock()
+	defer lock.Unlock()
+	return running
+}
+
+// Drain 不再開始新的撮合, 並等待進行中的撮合完成, 超過ctx期限時回傳ctx的錯誤
+func Drain(ctx context.Context) error {
+	lock.Lock()
+	draining = true
+	lock.Unlock()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for Running() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package limitOrder
+
+import (
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/shopspring/decimal"
+)
+
+// IsBuySide 開多倉及平空倉為買方, 開空倉及平多倉為賣方
+func IsBuySide(tradeType dbModels.TradeType, transactionType dbModels.TransactionType) bool {
+	if transactionType == dbModels.TransactionType_ClosePosition ||
+		transactionType == dbModels.TransactionType_Liquidation {
+		return tradeType == dbModels.TradeType_Sell
+	}
+	return tradeType == dbModels.TradeType_Buy
+}
+
+// IsMarketable 限價單在目前報價下是否可成交
+// 買方報價低於等於限價時成交, 賣方報價高於等於限價時成交
+func IsMarketable(tradeType dbModels.TradeType, transactionType dbModels.TransactionType, unitPrice, limitPrice decimal.Decimal) bool {
+	if IsBuySide(tradeType, transactionType) {
+		return unitPrice.LessThanOrEqual(limitPrice)
+	}
+	return unitPrice.GreaterThanOrEqual(limitPrice)
+}
@@ -0,0 +1,67 @@
+package margin
+
+import (
+	"github.com/paper-trade-chatbot/be-match/dao/marginRuleDao"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+var (
+	defaultMaintenanceRate = decimal.NewFromFloat(0.25)
+	defaultLiquidationRate = decimal.NewFromFloat(0.1)
+)
+
+// GetRule 依產品類別取得槓桿設定, 沒有設定時不開槓桿, 維持及強制平倉保證金率使用預設值
+func GetRule(db *gorm.DB, productType dbModels.ProductType) (*dbModels.MarginRuleModel, error) {
+	rule, err := marginRuleDao.GetByProductType(db, productType)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		rule = &dbModels.MarginRuleModel{
+			ProductType:     productType,
+			MaintenanceRate: defaultMaintenanceRate,
+			LiquidationRate: defaultLiquidationRate,
+		}
+	}
+	if !rule.Leverage.GreaterThan(decimal.NewFromInt(1)) {
+		rule.Leverage = decimal.NewFromInt(1)
+	}
+	return rule, nil
+}
+
+// GetLeverage 依產品類別取得槓桿倍數
+func GetLeverage(db *gorm.DB, productType dbModels.ProductType) (decimal.Decimal, error) {
+	rule, err := GetRule(db, productType)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return rule.Leverage, nil
+}
+
+// InitialMargin 開倉需要的保證金 = 成交金額 / 槓桿倍數
+func InitialMargin(notional, leverage decimal.Decimal) decimal.Decimal {
+	return notional.Div(leverage).Round(4)
+}
+
+// ReleasedMargin 依關倉數量占剩餘數量的比例釋放保證金
+// 沒有保證金帳戶的舊倉位視為沒有槓桿, 保證金為開倉淨值
+func ReleasedMargin(positionMargin *dbModels.PositionMarginModel, openPrice, closeAmount decimal.Decimal) decimal.Decimal {
+	if positionMargin == nil {
+		return openPrice.Mul(closeAmount)
+	}
+	if closeAmount.GreaterThanOrEqual(positionMargin.Amount) {
+		return positionMargin.Margin
+	}
+	return positionMargin.Margin.Mul(closeAmount).Div(positionMargin.Amount).Round(4)
+}
+
+// ProfitAndLoss 買: (關倉價 - 開倉價) * 數量, 賣: (開倉價 - 關倉價) * 數量
+func ProfitAndLoss(tradeType dbModels.TradeType, openPrice, closePrice, amount decimal.Decimal) decimal.Decimal {
+	pnl := closePrice.Sub(openPrice).Mul(amount)
+	if tradeType == dbModels.TradeType_Sell {
+		return pnl.Neg()
+	}
+	return pnl
+}
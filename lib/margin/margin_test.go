@@ -0,0 +1,85 @@
+package margin
+
+import (
+	"testing"
+
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/shopspring/decimal"
+)
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func TestInitialMargin(t *testing.T) {
+	tests := []struct {
+		name     string
+		notional decimal.Decimal
+		leverage decimal.Decimal
+		want     decimal.Decimal
+	}{
+		{"no leverage", d("1000"), d("1"), d("1000")},
+		{"leverage", d("1000"), d("4"), d("250")},
+		{"rounded to 4 places", d("100"), d("3"), d("33.3333")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InitialMargin(tt.notional, tt.leverage); !got.Equal(tt.want) {
+				t.Errorf("InitialMargin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReleasedMargin(t *testing.T) {
+	positionMargin := &dbModels.PositionMarginModel{
+		Amount: d("3"),
+		Margin: d("100"),
+	}
+
+	tests := []struct {
+		name           string
+		positionMargin *dbModels.PositionMarginModel
+		openPrice      decimal.Decimal
+		closeAmount    decimal.Decimal
+		want           decimal.Decimal
+	}{
+		{"no margin account", nil, d("50"), d("2"), d("100")},
+		{"partial close", positionMargin, d("50"), d("1"), d("33.3333")},
+		{"full close", positionMargin, d("50"), d("3"), d("100")},
+		{"over close", positionMargin, d("50"), d("4"), d("100")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReleasedMargin(tt.positionMargin, tt.openPrice, tt.closeAmount); !got.Equal(tt.want) {
+				t.Errorf("ReleasedMargin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProfitAndLoss(t *testing.T) {
+	tests := []struct {
+		name       string
+		tradeType  dbModels.TradeType
+		openPrice  decimal.Decimal
+		closePrice decimal.Decimal
+		amount     decimal.Decimal
+		want       decimal.Decimal
+	}{
+		{"buy profit", dbModels.TradeType_Buy, d("100"), d("110"), d("2"), d("20")},
+		{"buy loss", dbModels.TradeType_Buy, d("100"), d("90"), d("2"), d("-20")},
+		{"sell profit", dbModels.TradeType_Sell, d("100"), d("90"), d("2"), d("20")},
+		{"sell loss", dbModels.TradeType_Sell, d("100"), d("110"), d("2"), d("-20")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProfitAndLoss(tt.tradeType, tt.openPrice, tt.closePrice, tt.amount); !got.Equal(tt.want) {
+				t.Errorf("ProfitAndLoss() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package marketPrice
+
+import (
+	"context"
+
+	common "github.com/paper-trade-chatbot/be-common"
+	"github.com/paper-trade-chatbot/be-match/lib/limitOrder"
+	"github.com/paper-trade-chatbot/be-match/lib/pricing"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/paper-trade-chatbot/be-match/service"
+	"github.com/paper-trade-chatbot/be-proto/product"
+	"github.com/paper-trade-chatbot/be-proto/quote"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// GetProduct 以交易所代號及產品代號取得產品
+func GetProduct(ctx context.Context, exchangeCode, productCode string) (*product.Product, error) {
+	productRes, err := service.Impl.ProductIntf.GetProduct(ctx, &product.GetProductReq{
+		Product: &product.GetProductReq_Code{
+			Code: &product.ExchangeCodeProductCode{
+				ExchangeCode: exchangeCode,
+				ProductCode:  productCode,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if productRes.Product == nil {
+		return nil, common.ErrNoSuchProduct
+	}
+	return productRes.Product, nil
+}
+
+// GetPrice 取得產品目前報價, 與撮合相同, 買方取ask, 賣方取bid, 關倉時以平倉方向取價
+func GetPrice(ctx context.Context, productID int64, tradeType dbModels.TradeType, transactionType dbModels.TransactionType) (decimal.Decimal, error) {
+	flag := quote.GetQuotesReq_GetFlag_Bid
+	key := "bid"
+	if limitOrder.IsBuySide(tradeType, transactionType) {
+		flag = quote.GetQuotesReq_GetFlag_Ask
+		key = "ask"
+	}
+
+	getFrom := "000000"
+	getTo := "000000"
+	quoteRes, err := service.Impl.QuoteIntf.GetQuotes(ctx, &quote.GetQuotesReq{
+		ProductIDs: []int64{productID},
+		Flag:       flag,
+		GetFrom:    &getFrom,
+		GetTo:      &getTo,
+	})
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if len(quoteRes.Quotes) == 0 {
+		return decimal.Zero, common.ErrInternal
+	}
+
+	unitPriceString, ok := quoteRes.Quotes[0].Quotes[key]
+	if !ok {
+		return decimal.Zero, common.ErrInternal
+	}
+
+	return decimal.NewFromString(unitPriceString)
+}
+
+// GetExecutionPrice 與撮合相同以產品的成交價模型計算amount數量的成交價
+func GetExecutionPrice(ctx context.Context, db *gorm.DB, p *product.Product, tradeType dbModels.TradeType, transactionType dbModels.TransactionType, amount decimal.Decimal) (decimal.Decimal, error) {
+	pricingModel, err := pricing.GetPricingModel(db, p.ExchangeCode, p.Code)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	getFrom := "000000"
+	getTo := "000000"
+	quoteRes, err := service.Impl.QuoteIntf.GetQuotes(ctx, &quote.GetQuotesReq{
+		ProductIDs: []int64{p.Id},
+		Flag:       pricing.QuoteFlag,
+		GetFrom:    &getFrom,
+		GetTo:      &getTo,
+	})
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if len(quoteRes.Quotes) == 0 {
+		return decimal.Zero, common.ErrInternal
+	}
+
+	return pricingModel.Price(pricing.NewQuote(quoteRes.Quotes[0].Quotes), tradeType, transactionType, amount)
+}
@@ -0,0 +1,38 @@
+package matchError
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ErrCode uint32
+
+const (
+	//match
+	ErrCode_OrderNotFilledImmediately ErrCode = 11001
+	ErrCode_OrderExpired              ErrCode = 11002
+	ErrCode_NoQuote                   ErrCode = 11003
+	ErrCode_MarketClosed              ErrCode = 11004
+	ErrCode_StaleQuote                ErrCode = 11005
+	ErrCode_MatchInterrupted          ErrCode = 11006
+	ErrCode_MatchLockTimeout          ErrCode = 11007
+	ErrCode_CloseAmountExceeded       ErrCode = 11008
+	ErrCode_MatchRecordNotFound       ErrCode = 11009
+	ErrCode_MatchLockLost             ErrCode = 11010
+	ErrCode_UnsupportedTimeInForce    ErrCode = 11011
+)
+
+var (
+	//match
+	ErrOrderNotFilledImmediately = status.Error(codes.Code(ErrCode_OrderNotFilledImmediately), "order not filled immediately")
+	ErrOrderExpired              = status.Error(codes.Code(ErrCode_OrderExpired), "order expired")
+	ErrNoQuote                   = status.Error(codes.Code(ErrCode_NoQuote), "no quote for pricing")
+	ErrMarketClosed              = status.Error(codes.Code(ErrCode_MarketClosed), "market closed")
+	ErrStaleQuote                = status.Error(codes.Code(ErrCode_StaleQuote), "quote is stale")
+	ErrMatchInterrupted          = status.Error(codes.Code(ErrCode_MatchInterrupted), "match interrupted")
+	ErrMatchLockTimeout          = status.Error(codes.Code(ErrCode_MatchLockTimeout), "timed out waiting for match lock")
+	ErrCloseAmountExceeded       = status.Error(codes.Code(ErrCode_CloseAmountExceeded), "close amount exceeds position amount")
+	ErrMatchRecordNotFound       = status.Error(codes.Code(ErrCode_MatchRecordNotFound), "match record not found")
+	ErrMatchLockLost             = status.Error(codes.Code(ErrCode_MatchLockLost), "lease of match lock lost")
+	ErrUnsupportedTimeInForce    = status.Error(codes.Code(ErrCode_UnsupportedTimeInForce), "unsupported time in force")
+)
@@ -0,0 +1,236 @@
+package matchLock
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/paper-trade-chatbot/be-common/cache"
+	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-match/lib/matchError"
+)
+
+const (
+	// leaseDuration 鎖的租約, 持有期間每renewInterval延長一次, 當機時租約到期自動釋放
+	leaseDuration = 30 * time.Second
+	renewInterval = 10 * time.Second
+	pollInterval  = 100 * time.Millisecond
+	// waiterTimeout 等待者超過此時間沒有輪詢時視為已離開, 從等待佇列移除
+	waiterTimeout  = 5 * time.Second
+	acquireTimeout = 2 * time.Minute
+)
+
+// acquireScript 等待者依seq排入佇列, 只有排在最前面的等待者可以取得鎖
+// KEYS: 鎖, 等待佇列, 等待者心跳 ARGV: token, seq, 現在時間(ms), 租約(ms), 心跳逾時(ms)
+const acquireScript = `
+local now = tonumber(ARGV[3])
+redis.call('ZADD', KEYS[2], 'NX', ARGV[2], ARGV[1])
+redis.call('ZADD', KEYS[3], now + tonumber(ARGV[5]), ARGV[1])
+for _, waiter in ipairs(redis.call('ZRANGEBYSCORE', KEYS[3], '-inf', now)) do
+	redis.call('ZREM', KEYS[2], waiter)
+	redis.call('ZREM', KEYS[3], waiter)
+end
+redis.call('PEXPIRE', KEYS[2], ARGV[5])
+redis.call('PEXPIRE', KEYS[3], ARGV[5])
+local head = redis.call('ZRANGE', KEYS[2], 0, 0)
+if head[1] ~= ARGV[1] then
+	return 0
+end
+if not redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[4]) then
+	return 0
+end
+redis.call('ZREM', KEYS[2], ARGV[1])
+redis.call('ZREM', KEYS[3], ARGV[1])
+return 1
+`
+
+// leaveScript 放棄等待時離開佇列
+const leaveScript = `
+redis.call('ZREM', KEYS[1], ARGV[1])
+redis.call('ZREM', KEYS[2], ARGV[1])
+return 1
+`
+
+const renewScript = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`
+
+const releaseScript = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`
+
+// Lock 跨服務實例的分散式鎖
+type Lock struct {
+	key    string
+	token  string
+	cancel context.CancelFunc
+	done   chan struct{}
+	lost   chan struct{}
+}
+
+// Locks AcquireAll取得的一組鎖
+type Locks struct {
+	ctx   context.Context
+	locks []*Lock
+}
+
+// MemberKey 同一會員的撮合依序進行, 避免同時讀到相同餘額
+func MemberKey(memberID uint64) string {
+	return fmt.Sprintf("matchLock:member:%d", memberID)
+}
+
+// PositionKey 同一倉位的關倉依序進行
+func PositionKey(positionID uint64) string {
+	return fmt.Sprintf("matchLock:position:%d", positionID)
+}
+
+// Acquire 取得key的鎖, 同時等待的呼叫依seq由小到大取得
+// 超過acquireTimeout仍取不到時回傳ErrMatchLockTimeout
+func Acquire(ctx context.Context, key string, seq uint64) (*Lock, error) {
+	r, err := cache.GetRedis()
+	if err != nil {
+		return nil, err
+	}
+
+	token, _ := uuid.NewV4()
+	keys := []string{key, key + ":queue", key + ":waiters"}
+
+	ctxTimeout, cancel := context.WithTimeout(ctx, acquireTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		acquired, err := r.Eval(ctxTimeout, acquireScript, keys,
+			token.String(),
+			strconv.FormatUint(seq, 10),
+			time.Now().UnixMilli(),
+			leaseDuration.Milliseconds(),
+			waiterTimeout.Milliseconds(),
+		).Int()
+		if err == nil && acquired == 1 {
+			break
+		}
+		if err != nil {
+			logging.Warn(ctx, "[MatchLock] failed to acquire [%s]: %v", key, err)
+		}
+
+		select {
+		case <-ctxTimeout.Done():
+			if err := r.Eval(context.Background(), leaveScript, keys[1:], token.String()).Err(); err != nil {
+				logging.Error(ctx, "[MatchLock] failed to leave queue of [%s]: %v", key, err)
+			}
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, matchError.ErrMatchLockTimeout
+		case <-ticker.C:
+		}
+	}
+
+	ctxRenew, cancelRenew := context.WithCancel(context.Background())
+	lock := &Lock{
+		key:    key,
+		token:  token.String(),
+		cancel: cancelRenew,
+		done:   make(chan struct{}),
+		lost:   make(chan struct{}),
+	}
+	go lock.renew(ctxRenew, ctx)
+	return lock, nil
+}
+
+// AcquireAll 依傳入順序取得多個鎖, 任一失敗時釋放已取得的鎖
+// 呼叫端需以固定順序傳入key, 例如先會員後倉位, 避免互相等待
+func AcquireAll(ctx context.Context, seq uint64, keys ...string) (*Locks, error) {
+	locks := &Locks{ctx: ctx}
+	for _, key := range keys {
+		lock, err := Acquire(ctx, key, seq)
+		if err != nil {
+			locks.Release()
+			return nil, err
+		}
+		locks.locks = append(locks.locks, lock)
+	}
+	return locks, nil
+}
+
+// Release 依取得的相反順序釋放
+func (ls *Locks) Release() {
+	for i := len(ls.locks) - 1; i >= 0; i-- {
+		ls.locks[i].Release(ls.ctx)
+	}
+}
+
+// Check 任一鎖的租約已遺失時回傳ErrMatchLockLost, 扣款等有副作用的步驟前需先確認
+func (ls *Locks) Check() error {
+	for _, l := range ls.locks {
+		select {
+		case <-l.lost:
+			return matchError.ErrMatchLockLost
+		default:
+		}
+	}
+	return nil
+}
+
+func (l *Lock) renew(ctx context.Context, logCtx context.Context) {
+	defer close(l.done)
+
+	ticker := time.NewTicker(renewInterval)
+	defer ticker.Stop()
+
+	// 無法延長超過租約時間時, 鎖可能已被他人取得
+	renewedAt := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r, err := cache.GetRedis()
+			if err == nil {
+				var renewed int
+				renewed, err = r.Eval(ctx, renewScript, []string{l.key}, l.token, leaseDuration.Milliseconds()).Int()
+				if err == nil && renewed == 0 {
+					logging.Error(logCtx, "[MatchLock] lease of [%s] lost.", l.key)
+					close(l.lost)
+					return
+				}
+			}
+			if err != nil {
+				logging.Error(logCtx, "[MatchLock] failed to renew [%s]: %v", l.key, err)
+				if time.Since(renewedAt) >= leaseDuration {
+					logging.Error(logCtx, "[MatchLock] lease of [%s] expired.", l.key)
+					close(l.lost)
+					return
+				}
+				continue
+			}
+			renewedAt = time.Now()
+		}
+	}
+}
+
+// Release 停止延長租約並釋放鎖, 鎖已被他人取得時不處理
+func (l *Lock) Release(ctx context.Context) {
+	l.cancel()
+	<-l.done
+
+	r, err := cache.GetRedis()
+	if err != nil {
+		logging.Error(ctx, "[MatchLock] failed to release [%s]: %v", l.key, err)
+		return
+	}
+	if err := r.Eval(context.Background(), releaseScript, []string{l.key}, l.token).Err(); err != nil {
+		logging.Error(ctx, "[MatchLock] failed to release [%s]: %v", l.key, err)
+	}
+}
@@ -0,0 +1,209 @@
+package pricing
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/paper-trade-chatbot/be-match/dao/pricingRuleDao"
+	"github.com/paper-trade-chatbot/be-match/lib/limitOrder"
+	"github.com/paper-trade-chatbot/be-match/lib/matchError"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/paper-trade-chatbot/be-proto/quote"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// QuoteFlag 成交價模型需要同時取得ask及bid
+const QuoteFlag = quote.GetQuotesReq_GetFlag_Ask | quote.GetQuotesReq_GetFlag_Bid
+
+var bpsBase = decimal.NewFromInt(10000)
+
+// quoteTimeKey 報價服務在報價中附帶的報價時間, unix秒或毫秒
+// 報價服務未保證提供此欄位, 沒有時不做過期檢查
+const quoteTimeKey = "timestamp"
+
+// Quote 報價服務回傳的原始報價
+type Quote struct {
+	Ask  decimal.NullDecimal
+	Bid  decimal.NullDecimal
+	Time sql.NullTime
+}
+
+// NewQuote 解析GetQuotes回傳的報價, 無法解析的欄位視為沒有報價
+func NewQuote(quotes map[string]string) *Quote {
+	q := &Quote{}
+	if ask, ok := quotes["ask"]; ok {
+		if d, err := decimal.NewFromString(ask); err == nil {
+			q.Ask = decimal.NewNullDecimal(d)
+		}
+	}
+	if bid, ok := quotes["bid"]; ok {
+		if d, err := decimal.NewFromString(bid); err == nil {
+			q.Bid = decimal.NewNullDecimal(d)
+		}
+	}
+	if ts, ok := quotes[quoteTimeKey]; ok {
+		if unix, err := strconv.ParseInt(ts, 10, 64); err == nil {
+			if unix > 1e12 {
+				q.Time = sql.NullTime{Valid: true, Time: time.UnixMilli(unix)}
+			} else {
+				q.Time = sql.NullTime{Valid: true, Time: time.Unix(unix, 0)}
+			}
+		}
+	}
+	return q
+}
+
+// IsStale 報價時間超過maxAge時視為過期
+// maxAge為0或沒有報價時間時無法判斷, 不視為過期, 避免報價服務未提供時間時所有撮合都失敗
+func IsStale(q *Quote, maxAge time.Duration, now time.Time) bool {
+	if maxAge <= 0 {
+		return false
+	}
+	if !q.Time.Valid {
+		return false
+	}
+	return now.Sub(q.Time.Time) > maxAge
+}
+
+// GetMaxQuoteAge 取得產品報價最長可接受的時間, 沒有設定時回傳0
+func GetMaxQuoteAge(db *gorm.DB, exchangeCode, productCode string) (time.Duration, error) {
+	rule, err := pricingRuleDao.GetByProduct(db, exchangeCode, productCode)
+	if err != nil {
+		return 0, err
+	}
+	if rule == nil || !rule.MaxQuoteAge.Valid {
+		return 0, nil
+	}
+	return time.Duration(rule.MaxQuoteAge.Int64) * time.Second, nil
+}
+
+// PricingModel 由原始報價算出成交價, 關倉時以平倉方向取價
+type PricingModel interface {
+	Type() dbModels.PricingModel
+	Price(q *Quote, tradeType dbModels.TradeType, transactionType dbModels.TransactionType, amount decimal.Decimal) (decimal.Decimal, error)
+}
+
+// GetPricingModel 取得產品適用的成交價模型, 沒有設定時使用對手價
+func GetPricingModel(db *gorm.DB, exchangeCode, productCode string) (PricingModel, error) {
+	rule, err := pricingRuleDao.GetByProduct(db, exchangeCode, productCode)
+	if err != nil {
+		return nil, err
+	}
+	return New(rule), nil
+}
+
+// New 依設定建立成交價模型
+func New(rule *dbModels.PricingRuleModel) PricingModel {
+	if rule == nil {
+		return &touchModel{}
+	}
+
+	switch rule.PricingModel {
+	case dbModels.PricingModel_Mid:
+		return &midModel{}
+	case dbModels.PricingModel_FixedBps:
+		return &fixedBpsModel{
+			slippageBps: rule.SlippageBps,
+		}
+	case dbModels.PricingModel_MarketImpact:
+		return &marketImpactModel{
+			slippageBps:     rule.SlippageBps,
+			impactBps:       rule.ImpactBps,
+			referenceAmount: rule.ReferenceAmount,
+			maxSlippageBps:  rule.MaxSlippageBps,
+		}
+	}
+	return &touchModel{}
+}
+
+// touch 與撮合原本的取價相同, 買方用ask, 賣方用bid
+func touch(q *Quote, buy bool) (decimal.Decimal, error) {
+	price := q.Bid
+	if buy {
+		price = q.Ask
+	}
+	if !price.Valid {
+		return decimal.Zero, matchError.ErrNoQuote
+	}
+	return price.Decimal, nil
+}
+
+// slip 買方價格往上加, 賣方價格往下減
+func slip(price decimal.Decimal, buy bool, bps decimal.Decimal) decimal.Decimal {
+	slippage := price.Mul(bps).Div(bpsBase)
+	if buy {
+		return price.Add(slippage)
+	}
+	return price.Sub(slippage)
+}
+
+type touchModel struct{}
+
+func (m *touchModel) Type() dbModels.PricingModel {
+	return dbModels.PricingModel_Touch
+}
+
+func (m *touchModel) Price(q *Quote, tradeType dbModels.TradeType, transactionType dbModels.TransactionType, amount decimal.Decimal) (decimal.Decimal, error) {
+	return touch(q, limitOrder.IsBuySide(tradeType, transactionType))
+}
+
+type midModel struct{}
+
+func (m *midModel) Type() dbModels.PricingModel {
+	return dbModels.PricingModel_Mid
+}
+
+func (m *midModel) Price(q *Quote, tradeType dbModels.TradeType, transactionType dbModels.TransactionType, amount decimal.Decimal) (decimal.Decimal, error) {
+	if !q.Ask.Valid || !q.Bid.Valid {
+		return decimal.Zero, matchError.ErrNoQuote
+	}
+	return q.Ask.Decimal.Add(q.Bid.Decimal).Div(decimal.NewFromInt(2)), nil
+}
+
+type fixedBpsModel struct {
+	slippageBps decimal.Decimal
+}
+
+func (m *fixedBpsModel) Type() dbModels.PricingModel {
+	return dbModels.PricingModel_FixedBps
+}
+
+func (m *fixedBpsModel) Price(q *Quote, tradeType dbModels.TradeType, transactionType dbModels.TransactionType, amount decimal.Decimal) (decimal.Decimal, error) {
+	buy := limitOrder.IsBuySide(tradeType, transactionType)
+	price, err := touch(q, buy)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return slip(price, buy, m.slippageBps), nil
+}
+
+// marketImpactModel 滑價 = 基本滑價 + 每參考數量的滑價 * (數量 / 參考數量), 不超過滑價上限
+type marketImpactModel struct {
+	slippageBps     decimal.Decimal
+	impactBps       decimal.Decimal
+	referenceAmount decimal.NullDecimal
+	maxSlippageBps  decimal.NullDecimal
+}
+
+func (m *marketImpactModel) Type() dbModels.PricingModel {
+	return dbModels.PricingModel_MarketImpact
+}
+
+func (m *marketImpactModel) Price(q *Quote, tradeType dbModels.TradeType, transactionType dbModels.TransactionType, amount decimal.Decimal) (decimal.Decimal, error) {
+	buy := limitOrder.IsBuySide(tradeType, transactionType)
+	price, err := touch(q, buy)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	bps := m.slippageBps
+	if m.referenceAmount.Valid && m.referenceAmount.Decimal.IsPositive() {
+		bps = bps.Add(m.impactBps.Mul(amount.Abs()).Div(m.referenceAmount.Decimal))
+	}
+	if m.maxSlippageBps.Valid && bps.GreaterThan(m.maxSlippageBps.Decimal) {
+		bps = m.maxSlippageBps.Decimal
+	}
+	return slip(price, buy, bps), nil
+}
@@ -0,0 +1,250 @@
+package pricing
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/paper-trade-chatbot/be-match/lib/matchError"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/shopspring/decimal"
+)
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func nd(s string) decimal.NullDecimal {
+	return decimal.NewNullDecimal(d(s))
+}
+
+func TestNewQuote(t *testing.T) {
+	tests := []struct {
+		name   string
+		quotes map[string]string
+		want   Quote
+	}{
+		{
+			name:   "ask and bid",
+			quotes: map[string]string{"ask": "101.5", "bid": "100.5"},
+			want:   Quote{Ask: nd("101.5"), Bid: nd("100.5")},
+		},
+		{
+			name:   "unparsable fields",
+			quotes: map[string]string{"ask": "x", "bid": "", quoteTimeKey: "y"},
+			want:   Quote{},
+		},
+		{
+			name:   "unix seconds",
+			quotes: map[string]string{"ask": "1", quoteTimeKey: "1679000000"},
+			want:   Quote{Ask: nd("1"), Time: sql.NullTime{Valid: true, Time: time.Unix(1679000000, 0)}},
+		},
+		{
+			name:   "unix milliseconds",
+			quotes: map[string]string{"bid": "1", quoteTimeKey: "1679000000123"},
+			want:   Quote{Bid: nd("1"), Time: sql.NullTime{Valid: true, Time: time.UnixMilli(1679000000123)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewQuote(tt.quotes)
+			if got.Ask.Valid != tt.want.Ask.Valid || !got.Ask.Decimal.Equal(tt.want.Ask.Decimal) {
+				t.Errorf("ask = %v, want %v", got.Ask, tt.want.Ask)
+			}
+			if got.Bid.Valid != tt.want.Bid.Valid || !got.Bid.Decimal.Equal(tt.want.Bid.Decimal) {
+				t.Errorf("bid = %v, want %v", got.Bid, tt.want.Bid)
+			}
+			if got.Time.Valid != tt.want.Time.Valid || !got.Time.Time.Equal(tt.want.Time.Time) {
+				t.Errorf("time = %v, want %v", got.Time, tt.want.Time)
+			}
+		})
+	}
+}
+
+func TestIsStale(t *testing.T) {
+	now := time.Unix(1679000000, 0)
+
+	tests := []struct {
+		name   string
+		time   sql.NullTime
+		maxAge time.Duration
+		want   bool
+	}{
+		{"no max age", sql.NullTime{Valid: true, Time: now.Add(-time.Hour)}, 0, false},
+		{"missing time", sql.NullTime{}, time.Second, false},
+		{"fresh", sql.NullTime{Valid: true, Time: now.Add(-time.Second)}, 5 * time.Second, false},
+		{"at max age", sql.NullTime{Valid: true, Time: now.Add(-5 * time.Second)}, 5 * time.Second, false},
+		{"stale", sql.NullTime{Valid: true, Time: now.Add(-6 * time.Second)}, 5 * time.Second, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsStale(&Quote{Time: tt.time}, tt.maxAge, now); got != tt.want {
+				t.Errorf("IsStale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name string
+		rule *dbModels.PricingRuleModel
+		want dbModels.PricingModel
+	}{
+		{"no rule", nil, dbModels.PricingModel_Touch},
+		{"none", &dbModels.PricingRuleModel{}, dbModels.PricingModel_Touch},
+		{"mid", &dbModels.PricingRuleModel{PricingModel: dbModels.PricingModel_Mid}, dbModels.PricingModel_Mid},
+		{"fixed bps", &dbModels.PricingRuleModel{PricingModel: dbModels.PricingModel_FixedBps}, dbModels.PricingModel_FixedBps},
+		{"market impact", &dbModels.PricingRuleModel{PricingModel: dbModels.PricingModel_MarketImpact}, dbModels.PricingModel_MarketImpact},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.rule).Type(); got != tt.want {
+				t.Errorf("New().Type() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrice(t *testing.T) {
+	quote := &Quote{Ask: nd("101"), Bid: nd("99")}
+
+	tests := []struct {
+		name            string
+		model           PricingModel
+		quote           *Quote
+		tradeType       dbModels.TradeType
+		transactionType dbModels.TransactionType
+		amount          decimal.Decimal
+		want            decimal.Decimal
+		wantErr         error
+	}{
+		{
+			name:            "touch open buy uses ask",
+			model:           &touchModel{},
+			quote:           quote,
+			tradeType:       dbModels.TradeType_Buy,
+			transactionType: dbModels.TransactionType_OpenPosition,
+			want:            d("101"),
+		},
+		{
+			name:            "touch open sell uses bid",
+			model:           &touchModel{},
+			quote:           quote,
+			tradeType:       dbModels.TradeType_Sell,
+			transactionType: dbModels.TransactionType_OpenPosition,
+			want:            d("99"),
+		},
+		{
+			name:            "touch close buy sells at bid",
+			model:           &touchModel{},
+			quote:           quote,
+			tradeType:       dbModels.TradeType_Buy,
+			transactionType: dbModels.TransactionType_ClosePosition,
+			want:            d("99"),
+		},
+		{
+			name:            "touch liquidation sell buys at ask",
+			model:           &touchModel{},
+			quote:           quote,
+			tradeType:       dbModels.TradeType_Sell,
+			transactionType: dbModels.TransactionType_Liquidation,
+			want:            d("101"),
+		},
+		{
+			name:            "touch without ask",
+			model:           &touchModel{},
+			quote:           &Quote{Bid: nd("99")},
+			tradeType:       dbModels.TradeType_Buy,
+			transactionType: dbModels.TransactionType_OpenPosition,
+			wantErr:         matchError.ErrNoQuote,
+		},
+		{
+			name:            "mid",
+			model:           &midModel{},
+			quote:           quote,
+			tradeType:       dbModels.TradeType_Buy,
+			transactionType: dbModels.TransactionType_OpenPosition,
+			want:            d("100"),
+		},
+		{
+			name:            "mid without bid",
+			model:           &midModel{},
+			quote:           &Quote{Ask: nd("101")},
+			tradeType:       dbModels.TradeType_Sell,
+			transactionType: dbModels.TransactionType_OpenPosition,
+			wantErr:         matchError.ErrNoQuote,
+		},
+		{
+			name:            "fixed bps buy slips up",
+			model:           &fixedBpsModel{slippageBps: d("100")},
+			quote:           quote,
+			tradeType:       dbModels.TradeType_Buy,
+			transactionType: dbModels.TransactionType_OpenPosition,
+			want:            d("102.01"),
+		},
+		{
+			name:            "fixed bps sell slips down",
+			model:           &fixedBpsModel{slippageBps: d("100")},
+			quote:           quote,
+			tradeType:       dbModels.TradeType_Sell,
+			transactionType: dbModels.TransactionType_OpenPosition,
+			want:            d("98.01"),
+		},
+		{
+			name: "market impact scales with amount",
+			model: &marketImpactModel{
+				slippageBps:     d("10"),
+				impactBps:       d("20"),
+				referenceAmount: nd("100"),
+			},
+			quote:           quote,
+			tradeType:       dbModels.TradeType_Buy,
+			transactionType: dbModels.TransactionType_OpenPosition,
+			amount:          d("-50"),
+			want:            d("101.202"),
+		},
+		{
+			name: "market impact capped",
+			model: &marketImpactModel{
+				slippageBps:     d("10"),
+				impactBps:       d("20"),
+				referenceAmount: nd("100"),
+				maxSlippageBps:  nd("30"),
+			},
+			quote:           quote,
+			tradeType:       dbModels.TradeType_Sell,
+			transactionType: dbModels.TransactionType_OpenPosition,
+			amount:          d("1000"),
+			want:            d("98.703"),
+		},
+		{
+			name: "market impact without reference amount",
+			model: &marketImpactModel{
+				slippageBps: d("10"),
+				impactBps:   d("20"),
+			},
+			quote:           quote,
+			tradeType:       dbModels.TradeType_Buy,
+			transactionType: dbModels.TransactionType_OpenPosition,
+			amount:          d("1000"),
+			want:            d("101.101"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.model.Price(tt.quote, tt.tradeType, tt.transactionType, tt.amount)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Price() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !got.Equal(tt.want) {
+				t.Errorf("Price() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package retry
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/paper-trade-chatbot/be-match/lib/matchError"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxErrorLength 與match_record.retry_error的長度相同
+const maxErrorLength = 255
+
+// Policy 重試策略, 第n次重試前等待BaseDelay*2^(n-1), 最多MaxDelay, 並加上隨機抖動
+type Policy struct {
+	MaxAttempts int           // 含第一次的最多嘗試次數
+	BaseDelay   time.Duration // 第一次重試前的等待時間
+	MaxDelay    time.Duration // 單次等待時間上限
+	Deadline    time.Duration // 自第一次嘗試起的總時限, context的期限較早時以context為準
+}
+
+// retryableCodes 暫時性的錯誤才重試, 其餘例如餘額不足或參數錯誤直接失敗
+var retryableCodes = map[codes.Code]bool{
+	codes.Unavailable:                         true,
+	codes.DeadlineExceeded:                    true,
+	codes.ResourceExhausted:                   true,
+	codes.Aborted:                             true,
+	codes.Code(matchError.ErrCode_NoQuote):    true,
+	codes.Code(matchError.ErrCode_StaleQuote): true,
+}
+
+// IsRetryable 依gRPC status code判斷錯誤是否可以重試
+func IsRetryable(err error) bool {
+	return retryableCodes[status.Code(err)]
+}
+
+// Retrier 記錄一次撮合的重試次數及結果
+type Retrier struct {
+	ctx      context.Context
+	policy   Policy
+	deadline time.Time
+	attempts int
+	outcome  dbModels.RetryOutcome
+	lastErr  error
+}
+
+func New(ctx context.Context, policy Policy) *Retrier {
+	deadline := time.Now().Add(policy.Deadline)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	return &Retrier{
+		ctx:      ctx,
+		policy:   policy,
+		deadline: deadline,
+	}
+}
+
+// Next 第一次直接回傳true, 之後等待退避時間後回傳true
+// 次數用完, 等待後會超過總時限, 或context結束時回傳false
+func (r *Retrier) Next() bool {
+	if r.outcome != dbModels.RetryOutcome_None {
+		return false
+	}
+	if r.attempts == 0 {
+		r.attempts++
+		return true
+	}
+	if r.attempts >= r.policy.MaxAttempts {
+		r.outcome = dbModels.RetryOutcome_Exhausted
+		return false
+	}
+
+	delay := r.backoff()
+	if time.Now().Add(delay).After(r.deadline) {
+		r.outcome = dbModels.RetryOutcome_DeadlineExceeded
+		return false
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-r.ctx.Done():
+		r.outcome = dbModels.RetryOutcome_Cancelled
+		r.lastErr = r.ctx.Err()
+		return false
+	case <-timer.C:
+	}
+
+	r.attempts++
+	return true
+}
+
+// backoff 在計算出的等待時間的一半到全部之間隨機取值, 避免多個撮合同時重試
+func (r *Retrier) backoff() time.Duration {
+	delay := r.policy.BaseDelay << (r.attempts - 1)
+	if delay <= 0 || delay > r.policy.MaxDelay {
+		delay = r.policy.MaxDelay
+	}
+	half := int64(delay / 2)
+	if half <= 0 {
+		return delay
+	}
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// Retryable 記錄本次嘗試的錯誤, 回傳是否可以重試
+func (r *Retrier) Retryable(err error) bool {
+	r.lastErr = err
+	if !IsRetryable(err) {
+		r.outcome = dbModels.RetryOutcome_NonRetryable
+		return false
+	}
+	return true
+}
+
+// Succeed 記錄撮合成功
+func (r *Retrier) Succeed() {
+	r.outcome = dbModels.RetryOutcome_Succeeded
+}
+
+// Attempts 已嘗試的次數
+func (r *Retrier) Attempts() int {
+	return r.attempts
+}
+
+func (r *Retrier) Outcome() dbModels.RetryOutcome {
+	return r.outcome
+}
+
+// LastError 最後一次嘗試的錯誤訊息, 最多maxErrorLength個字
+func (r *Retrier) LastError() string {
+	if r.lastErr == nil {
+		return ""
+	}
+	message := []rune(r.lastErr.Error())
+	if s, ok := status.FromError(r.lastErr); ok {
+		message = []rune(s.Message())
+	}
+	if len(message) > maxErrorLength {
+		message = message[:maxErrorLength]
+	}
+	return string(message)
+}
@@ -0,0 +1,169 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paper-trade-chatbot/be-match/lib/matchError"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errors.New("boom"), false},
+		{"unavailable", status.Error(codes.Unavailable, "down"), true},
+		{"deadline exceeded", status.Error(codes.DeadlineExceeded, "slow"), true},
+		{"resource exhausted", status.Error(codes.ResourceExhausted, "busy"), true},
+		{"aborted", status.Error(codes.Aborted, "conflict"), true},
+		{"invalid argument", status.Error(codes.InvalidArgument, "bad"), false},
+		{"no quote", matchError.ErrNoQuote, true},
+		{"stale quote", matchError.ErrStaleQuote, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   Policy
+		attempts int
+		min      time.Duration
+		max      time.Duration
+	}{
+		{"first retry", Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}, 1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{"doubles", Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}, 3, 200 * time.Millisecond, 400 * time.Millisecond},
+		{"capped", Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}, 6, 500 * time.Millisecond, time.Second},
+		{"overflow capped", Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}, 64, 500 * time.Millisecond, time.Second},
+		{"too short to jitter", Policy{BaseDelay: 1, MaxDelay: 1}, 1, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Retrier{policy: tt.policy, attempts: tt.attempts}
+			for i := 0; i < 100; i++ {
+				if got := r.backoff(); got < tt.min || got > tt.max {
+					t.Fatalf("backoff() = %v, want between %v and %v", got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		policy       Policy
+		err          error
+		wantAttempts int
+		wantOutcome  dbModels.RetryOutcome
+	}{
+		{
+			name:         "exhausted",
+			ctx:          context.Background(),
+			policy:       Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Deadline: time.Second},
+			err:          status.Error(codes.Unavailable, "down"),
+			wantAttempts: 3,
+			wantOutcome:  dbModels.RetryOutcome_Exhausted,
+		},
+		{
+			name:         "deadline exceeded",
+			ctx:          context.Background(),
+			policy:       Policy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Second, Deadline: time.Millisecond},
+			err:          status.Error(codes.Unavailable, "down"),
+			wantAttempts: 1,
+			wantOutcome:  dbModels.RetryOutcome_DeadlineExceeded,
+		},
+		{
+			name:         "cancelled",
+			ctx:          cancelled,
+			policy:       Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Deadline: time.Second},
+			err:          status.Error(codes.Unavailable, "down"),
+			wantAttempts: 1,
+			wantOutcome:  dbModels.RetryOutcome_Cancelled,
+		},
+		{
+			name:         "non retryable",
+			ctx:          context.Background(),
+			policy:       Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Deadline: time.Second},
+			err:          status.Error(codes.InvalidArgument, "bad"),
+			wantAttempts: 1,
+			wantOutcome:  dbModels.RetryOutcome_NonRetryable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(tt.ctx, tt.policy)
+			for r.Next() {
+				if !r.Retryable(tt.err) {
+					break
+				}
+			}
+			if r.Attempts() != tt.wantAttempts {
+				t.Errorf("Attempts() = %d, want %d", r.Attempts(), tt.wantAttempts)
+			}
+			if r.Outcome() != tt.wantOutcome {
+				t.Errorf("Outcome() = %v, want %v", r.Outcome(), tt.wantOutcome)
+			}
+		})
+	}
+}
+
+func TestSucceed(t *testing.T) {
+	r := New(context.Background(), Policy{MaxAttempts: 3, Deadline: time.Second})
+	if !r.Next() {
+		t.Fatal("first Next() = false, want true")
+	}
+	r.Succeed()
+	if r.Next() {
+		t.Error("Next() after Succeed() = true, want false")
+	}
+	if r.Outcome() != dbModels.RetryOutcome_Succeeded {
+		t.Errorf("Outcome() = %v, want %v", r.Outcome(), dbModels.RetryOutcome_Succeeded)
+	}
+}
+
+func TestLastError(t *testing.T) {
+	long := strings.Repeat("界", maxErrorLength+10)
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"no error", nil, ""},
+		{"plain error", errors.New("boom"), "boom"},
+		{"status message only", status.Error(codes.Unavailable, "down"), "down"},
+		{"truncated by rune", errors.New(long), strings.Repeat("界", maxErrorLength)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Retrier{lastErr: tt.err}
+			if got := r.LastError(); got != tt.want {
+				t.Errorf("LastError() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package timeInForce
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	common "github.com/paper-trade-chatbot/be-common"
+	"github.com/paper-trade-chatbot/be-match/lib/matchError"
+	"github.com/paper-trade-chatbot/be-match/lib/retry"
+	"github.com/paper-trade-chatbot/be-match/lib/tradingCalendar"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+)
+
+// defaultRetryPolicy 報價或錢包服務短暫異常時, 以退避間隔重試, 總共約20秒
+// 總時限需小於關閉服務時等待撮合完成的時間(預設25秒), 撮合才不會在扣款與完成訂單之間被中斷
+var defaultRetryPolicy = retry.Policy{
+	MaxAttempts: 9,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+	Deadline:    20 * time.Second,
+}
+
+// RetryPolicy IOC只嘗試撮合一次, 其餘沿用預設的重試策略
+func RetryPolicy(tif dbModels.TimeInForce) retry.Policy {
+	policy := defaultRetryPolicy
+	if IsImmediate(tif) {
+		policy.MaxAttempts = 1
+	}
+	return policy
+}
+
+// IsImmediate IOC不可掛單
+// 撮合一律以整筆數量成交或不成交, 沒有部分成交, IOC即為FOK; FOK只是別名, 由IsSupported拒絕
+func IsImmediate(tif dbModels.TimeInForce) bool {
+	return tif == dbModels.TimeInForce_IOC || tif == dbModels.TimeInForce_FOK
+}
+
+// IsSupported 不接受FOK, 全部成交否則取消請使用IOC
+func IsSupported(tif dbModels.TimeInForce) bool {
+	return tif != dbModels.TimeInForce_FOK
+}
+
+// ExpireAt 計算DAY及GTD的到期時間, 其餘類別不會到期
+func ExpireAt(ctx context.Context, tif dbModels.TimeInForce, exchangeCode string, gtd *int64) (sql.NullTime, error) {
+	switch tif {
+	case dbModels.TimeInForce_GTD:
+		if gtd == nil {
+			return sql.NullTime{}, common.ErrNoRequiredParam
+		}
+		expireAt := time.Unix(*gtd, 0)
+		if !expireAt.After(time.Now()) {
+			return sql.NullTime{}, matchError.ErrOrderExpired
+		}
+		return sql.NullTime{Valid: true, Time: expireAt}, nil
+
+	case dbModels.TimeInForce_DAY:
+		// 與開盤判斷相同依交易日曆, 到期時間為下一次收盤
+		closeAt, err := tradingCalendar.NextClose(ctx, exchangeCode, time.Now())
+		if err != nil {
+			return sql.NullTime{}, err
+		}
+		return sql.NullTime{Valid: true, Time: closeAt}, nil
+	}
+
+	return sql.NullTime{}, nil
+}
@@ -0,0 +1,285 @@
+package tradingCalendar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-match/service"
+	"github.com/paper-trade-chatbot/be-proto/product"
+)
+
+const dateLayout = "2006-01-02"
+
+// closeSearchDays 往後找收盤時間的天數, 涵蓋長假
+const closeSearchDays = 14
+
+// calendarFile 交易日曆檔, 以交易所代號為key, 例:
+//
+//	{
+//	  "NASDAQ": {
+//	    "timezone": "America/New_York",
+//	    "weekdays": [1, 2, 3, 4, 5],
+//	    "sessions": [{"open": "09:30", "close": "16:00"}],
+//	    "holidays": ["2023-01-02", "2023-01-16"],
+//	    "halfDays": {"2023-11-24": "13:00"}
+//	  }
+//	}
+//
+// weekdays以0為週日, sessions為當地時間, halfDays為提早收盤的日期及收盤時間
+type calendarFile map[string]struct {
+	Timezone string            `json:"timezone"`
+	Weekdays []time.Weekday    `json:"weekdays"`
+	Sessions []sessionFile     `json:"sessions"`
+	Holidays []string          `json:"holidays"`
+	HalfDays map[string]string `json:"halfDays"`
+}
+
+type sessionFile struct {
+	Open  string `json:"open"`
+	Close string `json:"close"`
+}
+
+// session 以當地午夜起算的開收盤時間, 收盤早於開盤時代表跨夜
+type session struct {
+	open  time.Duration
+	close time.Duration
+}
+
+type timeRange struct {
+	start time.Time
+	end   time.Time
+}
+
+type calendar struct {
+	location  *time.Location
+	weekdays  map[time.Weekday]bool // nil代表每天都開盤
+	sessions  []session             // 空白代表全天開盤
+	holidays  map[string]bool
+	halfDays  map[string]time.Duration
+	trade     []timeRange // 例外開盤時段
+	stopTrade []timeRange // 例外休市時段
+}
+
+var calendars = map[string]*calendar{}
+
+// Initialize 讀取TRADING_CALENDAR_FILE指定的交易日曆檔
+// 未設定時或交易所不在檔案中時, 以product服務的交易所設定判斷開盤時間
+func Initialize(ctx context.Context) {
+	path, ok := os.LookupEnv("TRADING_CALENDAR_FILE")
+	if !ok || path == "" {
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+	file := calendarFile{}
+	if err := json.Unmarshal(data, &file); err != nil {
+		panic(err)
+	}
+
+	for exchangeCode, f := range file {
+		location, err := time.LoadLocation(f.Timezone)
+		if err != nil {
+			panic(fmt.Sprintf("trading calendar [%s]: %v", exchangeCode, err))
+		}
+		c := &calendar{
+			location: location,
+			holidays: map[string]bool{},
+			halfDays: map[string]time.Duration{},
+		}
+		if len(f.Weekdays) > 0 {
+			c.weekdays = map[time.Weekday]bool{}
+			for _, w := range f.Weekdays {
+				c.weekdays[w] = true
+			}
+		}
+		for _, s := range f.Sessions {
+			open, err := parseClock(s.Open)
+			if err != nil {
+				panic(fmt.Sprintf("trading calendar [%s]: %v", exchangeCode, err))
+			}
+			close, err := parseClock(s.Close)
+			if err != nil {
+				panic(fmt.Sprintf("trading calendar [%s]: %v", exchangeCode, err))
+			}
+			c.sessions = append(c.sessions, session{open: open, close: close})
+		}
+		for _, h := range f.Holidays {
+			c.holidays[h] = true
+		}
+		for d, t := range f.HalfDays {
+			close, err := parseClock(t)
+			if err != nil {
+				panic(fmt.Sprintf("trading calendar [%s]: %v", exchangeCode, err))
+			}
+			c.halfDays[d] = close
+		}
+		calendars[exchangeCode] = c
+	}
+	logging.Info(ctx, "[TradingCalendar] loaded %d exchanges from %s", len(calendars), path)
+}
+
+// IsOpen 交易所在now時是否開盤
+func IsOpen(ctx context.Context, exchangeCode string, now time.Time) (bool, error) {
+	c, err := getCalendar(ctx, exchangeCode)
+	if err != nil {
+		return false, err
+	}
+	return c.isOpen(now), nil
+}
+
+// NextClose 交易所在now之後的下一次收盤時間, 依開盤時段, 假日, 半日市及例外時段判斷
+// 全天開盤沒有收盤時以當地午夜為收盤
+func NextClose(ctx context.Context, exchangeCode string, now time.Time) (time.Time, error) {
+	c, err := getCalendar(ctx, exchangeCode)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return c.nextClose(now), nil
+}
+
+func getCalendar(ctx context.Context, exchangeCode string) (*calendar, error) {
+	if c, ok := calendars[exchangeCode]; ok {
+		return c, nil
+	}
+	exchangeRes, err := service.Impl.ProductIntf.GetExchange(ctx, &product.GetExchangeReq{
+		Code: exchangeCode,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fromExchange(exchangeRes.Exchange), nil
+}
+
+// fromExchange 以product服務的交易所設定建立日曆, 沒有假日及半日市
+func fromExchange(exchange *product.Exchange) *calendar {
+	location := time.FixedZone(exchange.Code, int(exchange.TimezoneOffset*float64(time.Hour/time.Second)))
+	if exchange.Location != "" {
+		if l, err := time.LoadLocation(exchange.Location); err == nil {
+			location = l
+		}
+	}
+
+	c := &calendar{location: location}
+	if exchange.ExchangeDay != nil {
+		c.weekdays = map[time.Weekday]bool{}
+		for d := exchange.ExchangeDay.StartDay; d <= exchange.ExchangeDay.EndDay; d++ {
+			c.weekdays[time.Weekday(d%7)] = true
+		}
+	}
+	if exchange.OpenTime != nil && exchange.CloseTime != nil {
+		c.sessions = []session{{
+			open:  time.Duration(*exchange.OpenTime) * time.Second,
+			close: time.Duration(*exchange.CloseTime) * time.Second,
+		}}
+	}
+	if exchange.ExceptionTime != nil {
+		for _, t := range exchange.ExceptionTime.Trade {
+			c.trade = append(c.trade, timeRange{start: time.Unix(t.Start, 0), end: time.Unix(t.End, 0)})
+		}
+		for _, t := range exchange.ExceptionTime.StopTrade {
+			c.stopTrade = append(c.stopTrade, timeRange{start: time.Unix(t.Start, 0), end: time.Unix(t.End, 0)})
+		}
+	}
+	return c
+}
+
+func (c *calendar) isOpen(now time.Time) bool {
+	for _, r := range c.stopTrade {
+		if !now.Before(r.start) && now.Before(r.end) {
+			return false
+		}
+	}
+	for _, r := range c.trade {
+		if !now.Before(r.start) && now.Before(r.end) {
+			return true
+		}
+	}
+
+	local := now.In(c.location)
+	date := local.Format(dateLayout)
+	if c.holidays[date] {
+		return false
+	}
+	if c.weekdays != nil && !c.weekdays[local.Weekday()] {
+		return false
+	}
+	if len(c.sessions) == 0 {
+		return true
+	}
+
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, c.location)
+	clock := local.Sub(midnight)
+	halfDayClose, isHalfDay := c.halfDays[date]
+	for _, s := range c.sessions {
+		close := s.close
+		if isHalfDay && halfDayClose < close {
+			close = halfDayClose
+		}
+		if close > s.open {
+			if clock >= s.open && clock < close {
+				return true
+			}
+			continue
+		}
+		// 跨夜時段
+		if clock >= s.open || clock < close {
+			return true
+		}
+	}
+	return false
+}
+
+// nextClose 收盤時間只會是各日的時段收盤, 半日市收盤, 午夜或例外時段的邊界,
+// 從中找出now之後第一個由開盤轉為休市的時間
+func (c *calendar) nextClose(now time.Time) time.Time {
+	local := now.In(c.location)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, c.location)
+
+	candidates := []time.Time{}
+	for i := 0; i <= closeSearchDays; i++ {
+		midnight := today.AddDate(0, 0, i)
+		candidates = append(candidates, midnight)
+		for _, s := range c.sessions {
+			candidates = append(candidates, midnight.Add(s.close))
+		}
+		if halfDayClose, ok := c.halfDays[midnight.Format(dateLayout)]; ok {
+			candidates = append(candidates, midnight.Add(halfDayClose))
+		}
+	}
+	for _, r := range c.trade {
+		candidates = append(candidates, r.end)
+	}
+	for _, r := range c.stopTrade {
+		candidates = append(candidates, r.start)
+	}
+
+	var closeAt time.Time
+	for _, t := range candidates {
+		if !t.After(now) || c.isOpen(t) || !c.isOpen(t.Add(-time.Second)) {
+			continue
+		}
+		if closeAt.IsZero() || t.Before(closeAt) {
+			closeAt = t
+		}
+	}
+	if closeAt.IsZero() {
+		closeAt = today.AddDate(0, 0, 1)
+	}
+	return closeAt
+}
+
+// parseClock 將"15:04"轉為午夜起算的時間
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,133 @@
+package tradingCalendar
+
+import (
+	"testing"
+	"time"
+)
+
+var est = time.FixedZone("EST", -5*60*60)
+
+func at(day, hour, min int) time.Time {
+	return time.Date(2023, time.January, day, hour, min, 0, 0, est)
+}
+
+// newCalendar 週一至週五09:30-16:00開盤, 2023-01-13半日市13:00收盤, 2023-01-16休市
+func newCalendar() *calendar {
+	return &calendar{
+		location: est,
+		weekdays: map[time.Weekday]bool{
+			time.Monday:    true,
+			time.Tuesday:   true,
+			time.Wednesday: true,
+			time.Thursday:  true,
+			time.Friday:    true,
+		},
+		sessions: []session{{open: 9*time.Hour + 30*time.Minute, close: 16 * time.Hour}},
+		holidays: map[string]bool{"2023-01-16": true},
+		halfDays: map[string]time.Duration{"2023-01-13": 13 * time.Hour},
+	}
+}
+
+func TestIsOpen(t *testing.T) {
+	stopTrade := newCalendar()
+	stopTrade.stopTrade = []timeRange{{start: at(12, 11, 0), end: at(12, 12, 0)}}
+	trade := newCalendar()
+	trade.trade = []timeRange{{start: at(14, 10, 0), end: at(14, 11, 0)}}
+	overnight := &calendar{
+		location: est,
+		sessions: []session{{open: 18 * time.Hour, close: 5 * time.Hour}},
+	}
+
+	tests := []struct {
+		name     string
+		calendar *calendar
+		now      time.Time
+		want     bool
+	}{
+		{"in session", newCalendar(), at(12, 10, 0), true},
+		{"before open", newCalendar(), at(12, 9, 0), false},
+		{"at open", newCalendar(), at(12, 9, 30), true},
+		{"at close", newCalendar(), at(12, 16, 0), false},
+		{"other timezone", newCalendar(), time.Date(2023, time.January, 12, 15, 0, 0, 0, time.UTC), true},
+		{"weekend", newCalendar(), at(14, 10, 0), false},
+		{"holiday", newCalendar(), at(16, 10, 0), false},
+		{"before half day close", newCalendar(), at(13, 12, 59), true},
+		{"after half day close", newCalendar(), at(13, 13, 0), false},
+		{"stop trade", stopTrade, at(12, 11, 30), false},
+		{"after stop trade", stopTrade, at(12, 12, 0), true},
+		{"exception trade", trade, at(14, 10, 30), true},
+		{"after exception trade", trade, at(14, 11, 0), false},
+		{"overnight evening", overnight, at(12, 20, 0), true},
+		{"overnight morning", overnight, at(13, 4, 0), true},
+		{"overnight closed", overnight, at(13, 12, 0), false},
+		{"all day", &calendar{location: est}, at(14, 3, 0), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.calendar.isOpen(tt.now); got != tt.want {
+				t.Errorf("isOpen(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextClose(t *testing.T) {
+	stopTrade := newCalendar()
+	stopTrade.stopTrade = []timeRange{{start: at(12, 11, 0), end: at(12, 12, 0)}}
+	trade := newCalendar()
+	trade.trade = []timeRange{{start: at(14, 10, 0), end: at(14, 11, 0)}}
+	overnight := &calendar{
+		location: est,
+		sessions: []session{{open: 18 * time.Hour, close: 5 * time.Hour}},
+	}
+
+	tests := []struct {
+		name     string
+		calendar *calendar
+		now      time.Time
+		want     time.Time
+	}{
+		{"in session", newCalendar(), at(12, 10, 0), at(12, 16, 0)},
+		{"after close", newCalendar(), at(12, 17, 0), at(13, 13, 0)},
+		{"over weekend and holiday", newCalendar(), at(13, 14, 0), at(17, 16, 0)},
+		{"stop trade", stopTrade, at(12, 10, 0), at(12, 11, 0)},
+		{"exception trade", trade, at(14, 10, 30), at(14, 11, 0)},
+		{"overnight", overnight, at(12, 20, 0), at(13, 5, 0)},
+		{"all day closes at midnight", &calendar{location: est}, at(12, 10, 0), at(13, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.calendar.nextClose(tt.now); !got.Equal(tt.want) {
+				t.Errorf("nextClose(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		clock   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"00:00", 0, false},
+		{"09:30", 9*time.Hour + 30*time.Minute, false},
+		{"23:59", 23*time.Hour + 59*time.Minute, false},
+		{"24:00", 0, true},
+		{"9", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.clock, func(t *testing.T) {
+			got, err := parseClock(tt.clock)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseClock() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseClock() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package trailingStop
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/paper-trade-chatbot/be-common/cache"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/shopspring/decimal"
+)
+
+const watermarkTTL = 7 * 24 * time.Hour
+
+// updateWatermarkScript 多倉保留最高價, 空倉保留最低價, 每次更新都延長過期時間
+const updateWatermarkScript = `
+local current = redis.call('GET', KEYS[1])
+if (not current)
+	or (ARGV[2] == 'max' and tonumber(ARGV[1]) > tonumber(current))
+	or (ARGV[2] == 'min' and tonumber(ARGV[1]) < tonumber(current)) then
+	current = ARGV[1]
+	redis.call('SET', KEYS[1], current)
+end
+redis.call('EXPIRE', KEYS[1], ARGV[3])
+return current
+`
+
+func watermarkKey(triggerID uint64) string {
+	return "trailingStop:watermark:" + strconv.FormatUint(triggerID, 10)
+}
+
+// UpdateWatermark 以最新報價更新移動停損的高低水位, 回傳更新後的水位
+func UpdateWatermark(ctx context.Context, triggerID uint64, tradeType dbModels.TradeType, unitPrice decimal.Decimal) (decimal.Decimal, error) {
+	r, _ := cache.GetRedis()
+
+	direction := "min"
+	if tradeType == dbModels.TradeType_Buy {
+		direction = "max"
+	}
+
+	value, err := r.Eval(ctx, updateWatermarkScript, []string{watermarkKey(triggerID)},
+		unitPrice.String(), direction, int64(watermarkTTL/time.Second)).Text()
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return decimal.NewFromString(value)
+}
+
+// ClearWatermark 觸發或取消後刪除水位
+func ClearWatermark(ctx context.Context, triggerID uint64) error {
+	r, _ := cache.GetRedis()
+	return r.Del(ctx, watermarkKey(triggerID)).Err()
+}
+
+// StopPrice 依水位計算目前的停損價, 多倉在水位之下, 空倉在水位之上
+func StopPrice(trailMode dbModels.TrailMode, trailValue decimal.Decimal, tradeType dbModels.TradeType, watermark decimal.Decimal) decimal.Decimal {
+	distance := trailValue
+	if trailMode == dbModels.TrailMode_Percent {
+		distance = watermark.Mul(trailValue).Div(decimal.NewFromInt(100))
+	}
+
+	if tradeType == dbModels.TradeType_Buy {
+		return watermark.Sub(distance)
+	}
+	return watermark.Add(distance)
+}
@@ -0,0 +1,43 @@
+package trailingStop
+
+import (
+	"testing"
+
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/shopspring/decimal"
+)
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func TestStopPrice(t *testing.T) {
+	tests := []struct {
+		name       string
+		trailMode  dbModels.TrailMode
+		trailValue decimal.Decimal
+		tradeType  dbModels.TradeType
+		watermark  decimal.Decimal
+		want       decimal.Decimal
+	}{
+		{"buy price", dbModels.TrailMode_Price, d("5"), dbModels.TradeType_Buy, d("100"), d("95")},
+		{"sell price", dbModels.TrailMode_Price, d("5"), dbModels.TradeType_Sell, d("100"), d("105")},
+		{"buy percent", dbModels.TrailMode_Percent, d("2.5"), dbModels.TradeType_Buy, d("200"), d("195")},
+		{"sell percent", dbModels.TrailMode_Percent, d("2.5"), dbModels.TradeType_Sell, d("200"), d("205")},
+		{"zero trail", dbModels.TrailMode_Percent, d("0"), dbModels.TradeType_Buy, d("200"), d("200")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StopPrice(tt.trailMode, tt.trailValue, tt.tradeType, tt.watermark); !got.Equal(tt.want) {
+				t.Errorf("StopPrice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatermarkKey(t *testing.T) {
+	if got, want := watermarkKey(42), "trailingStop:watermark:42"; got != want {
+		t.Errorf("watermarkKey() = %q, want %q", got, want)
+	}
+}
@@ -2,23 +2,38 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
-
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/paper-trade-chatbot/be-common/api"
 	"github.com/paper-trade-chatbot/be-common/cache"
 	"github.com/paper-trade-chatbot/be-common/database"
 	"github.com/paper-trade-chatbot/be-match/cronjob"
+	"github.com/paper-trade-chatbot/be-match/health"
+	"github.com/paper-trade-chatbot/be-match/lib/inflight"
+	"github.com/paper-trade-chatbot/be-match/lib/tradingCalendar"
+	"github.com/paper-trade-chatbot/be-match/rpc"
 	"github.com/paper-trade-chatbot/be-match/service"
+	"github.com/paper-trade-chatbot/be-match/workjob"
 
 	"github.com/paper-trade-chatbot/be-common/config"
 	"github.com/paper-trade-chatbot/be-common/global"
 	"github.com/paper-trade-chatbot/be-common/logging"
-	"github.com/paper-trade-chatbot/be-common/server"
 	"github.com/paper-trade-chatbot/be-match/pubsub"
 )
 
+// defaultDrainTimeout 關閉服務時等待撮合完成的時間, 需大於撮合重試的總時限
+const defaultDrainTimeout = 25 * time.Second
+
 func main() {
 
-	global.Alive = true
+	health.SetAlive(true)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -40,20 +55,86 @@ func main() {
 
 	initConfig()
 
+	tradingCalendar.Initialize(ctx)
+
+	// 撮合紀錄查詢的gRPC服務
+	rpc.Initialize(ctx)
+	defer rpc.Finalize(ctx)
+
 	address := fmt.Sprintf("%s:%s",
 		config.GetString("SERVER_LISTEN_ADDRESS"),
 		config.GetString("SERVER_LISTEN_PORT"))
-	httpServer := server.CreateHttpServer(ctx, address)
+	health.Initialize(ctx)
+	// 不使用server.CreateHttpServer, 收到訊號時由gracefulShutdown在撮合完成後才關閉
+	httpServer := &http.Server{
+		Addr:    address,
+		Handler: api.GetRouter(),
+	}
 
-	go cronjob.Cron()
+	cronjob.Cron()
 
-	global.Ready = true
+	workjob.Initialize(ctx)
+	defer workjob.Finalize(ctx)
+
+	// 依相依服務的檢查結果設定readiness
+	health.Start(ctx)
 
 	logging.Info(ctx, "Initialization complete, listening on %s...", address)
-	if err := httpServer.ListenAndServe(); err != nil {
-		logging.Info(ctx, err.Error())
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.ListenAndServe()
+	}()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case sig := <-sigChan:
+		logging.Warn(ctx, "Received signal: %s.", sig.String())
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			logging.Error(ctx, err.Error())
+		}
 	}
 
+	gracefulShutdown(ctx, httpServer)
+}
+
+// gracefulShutdown 停止消費訊息, cronjob及開始新的撮合, 等進行中的撮合完成後才關閉HTTP server,
+// 再由defer關閉gRPC, DB及Redis連線, 避免撮合在扣款與完成訂單之間被中斷
+func gracefulShutdown(ctx context.Context, httpServer *http.Server) {
+	health.Finalize(ctx)
+	health.SetReady(false)
+
+	ctxTimeout, cancel := context.WithTimeout(ctx, drainTimeout())
+	defer cancel()
+
+	logging.Warn(ctx, "Draining in-flight matches...")
+	pubsub.StopConsuming(ctx)
+	if err := cronjob.Stop(ctxTimeout); err != nil {
+		logging.Error(ctx, "cronjobs still running after drain timeout: %v", err)
+	}
+	if err := inflight.Drain(ctxTimeout); err != nil {
+		logging.Error(ctx, "%d matches still running after drain timeout: %v", inflight.Running(), err)
+	}
+	if err := pubsub.Drain(ctxTimeout); err != nil {
+		logging.Error(ctx, "subscribers still running after drain timeout: %v", err)
+	}
+	logging.Warn(ctx, "Drain complete, shutting down.")
+
+	health.SetAlive(false)
+	if err := httpServer.Shutdown(ctxTimeout); err != nil {
+		logging.Error(ctx, "failed to shut down http server: %v", err)
+	}
+}
+
+// drainTimeout 需小於kubernetes的terminationGracePeriodSeconds, 並大於撮合重試的總時限
+func drainTimeout() time.Duration {
+	if v, ok := os.LookupEnv("SHUTDOWN_DRAIN_TIMEOUT_MS"); ok {
+		if ms, err := strconv.Atoi(v); err == nil && ms > 0 {
+			return time.Duration(ms) * time.Millisecond
+		}
+	}
+	return defaultDrainTimeout
 }
 
 func initConfig() {
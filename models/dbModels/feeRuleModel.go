@@ -0,0 +1,21 @@
+package dbModels
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type FeeRuleModel struct {
+	ID           uint64              `gorm:"column:id; primary_key"`
+	ExchangeCode string              `gorm:"column:exchange_code"`
+	ProductCode  string              `gorm:"column:product_code"`
+	OpenFlatFee  decimal.Decimal     `gorm:"column:open_flat_fee"`
+	OpenRate     decimal.Decimal     `gorm:"column:open_rate"`
+	CloseFlatFee decimal.Decimal     `gorm:"column:close_flat_fee"`
+	CloseRate    decimal.Decimal     `gorm:"column:close_rate"`
+	MinFee       decimal.NullDecimal `gorm:"column:min_fee"`
+	MaxFee       decimal.NullDecimal `gorm:"column:max_fee"`
+	CreatedAt    time.Time           `gorm:"column:created_at"`
+	UpdatedAt    time.Time           `gorm:"column:updated_at"`
+}
@@ -0,0 +1,47 @@
+package dbModels
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type FinancingStatus int
+
+const (
+	FinancingStatus_None       FinancingStatus = iota
+	FinancingStatus_Processing                 // 處理中
+	FinancingStatus_Charged                    // 已扣款
+	FinancingStatus_Failed                     // 失敗
+)
+
+type FinancingRuleModel struct {
+	ID           uint64          `gorm:"column:id; primary_key"`
+	ExchangeCode string          `gorm:"column:exchange_code"`
+	ProductCode  string          `gorm:"column:product_code"`
+	LongRate     decimal.Decimal `gorm:"column:long_rate"`
+	ShortRate    decimal.Decimal `gorm:"column:short_rate"`
+	DaysPerYear  int64           `gorm:"column:days_per_year"`
+	CreatedAt    time.Time       `gorm:"column:created_at"`
+	UpdatedAt    time.Time       `gorm:"column:updated_at"`
+}
+
+type FinancingChargeModel struct {
+	ID              uint64          `gorm:"column:id; primary_key"`
+	PositionID      uint64          `gorm:"column:position_id"`
+	ChargeDate      time.Time       `gorm:"column:charge_date"`
+	MemberID        uint64          `gorm:"column:member_id"`
+	ExchangeCode    string          `gorm:"column:exchange_code"`
+	ProductCode     string          `gorm:"column:product_code"`
+	TradeType       TradeType       `gorm:"column:trade_type"`
+	FinancingStatus FinancingStatus `gorm:"column:financing_status"`
+	UnitPrice       decimal.Decimal `gorm:"column:unit_price"`
+	Amount          decimal.Decimal `gorm:"column:amount"`
+	Principal       decimal.Decimal `gorm:"column:principal"`
+	Rate            decimal.Decimal `gorm:"column:rate"`
+	Fee             decimal.Decimal `gorm:"column:fee"`
+	TransactionID   sql.NullInt64   `gorm:"column:transaction_id"`
+	CreatedAt       time.Time       `gorm:"column:created_at"`
+	UpdatedAt       time.Time       `gorm:"column:updated_at"`
+}
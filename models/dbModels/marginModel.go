@@ -0,0 +1,73 @@
+package dbModels
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type ProductType int
+
+const (
+	ProductType_None    ProductType = iota // 所有類別
+	ProductType_Stock                      // 股票
+	ProductType_Crypto                     // 加密貨幣
+	ProductType_Forex                      // 外匯
+	ProductType_Futures                    // 期貨
+)
+
+type MarginStatus int
+
+const (
+	MarginStatus_None     MarginStatus = iota
+	MarginStatus_Open                  // 占用中
+	MarginStatus_Released              // 已釋放
+)
+
+type MarginCallType int
+
+const (
+	MarginCallType_None        MarginCallType = iota
+	MarginCallType_MarginCall                 // 追繳保證金
+	MarginCallType_Liquidation                // 強制平倉
+)
+
+type MarginRuleModel struct {
+	ID              uint64          `gorm:"column:id; primary_key"`
+	ProductType     ProductType     `gorm:"column:product_type"`
+	Leverage        decimal.Decimal `gorm:"column:leverage"`
+	MaintenanceRate decimal.Decimal `gorm:"column:maintenance_rate"`
+	LiquidationRate decimal.Decimal `gorm:"column:liquidation_rate"`
+	CreatedAt       time.Time       `gorm:"column:created_at"`
+	UpdatedAt       time.Time       `gorm:"column:updated_at"`
+}
+
+type PositionMarginModel struct {
+	ID           uint64          `gorm:"column:id; primary_key"`
+	PositionID   uint64          `gorm:"column:position_id"`
+	MemberID     uint64          `gorm:"column:member_id"`
+	ExchangeCode string          `gorm:"column:exchange_code"`
+	ProductCode  string          `gorm:"column:product_code"`
+	TradeType    TradeType       `gorm:"column:trade_type"`
+	MarginStatus MarginStatus    `gorm:"column:margin_status"`
+	Leverage     decimal.Decimal `gorm:"column:leverage"`
+	OpenPrice    decimal.Decimal `gorm:"column:open_price"`
+	Amount       decimal.Decimal `gorm:"column:amount"`
+	Margin       decimal.Decimal `gorm:"column:margin"`
+	CreatedAt    time.Time       `gorm:"column:created_at"`
+	UpdatedAt    time.Time       `gorm:"column:updated_at"`
+}
+
+type MarginCallModel struct {
+	ID                uint64          `gorm:"column:id; primary_key"`
+	MemberID          uint64          `gorm:"column:member_id"`
+	CurrencyCode      string          `gorm:"column:currency_code"`
+	MarginCallType    MarginCallType  `gorm:"column:margin_call_type"`
+	Equity            decimal.Decimal `gorm:"column:equity"`
+	MaintenanceMargin decimal.Decimal `gorm:"column:maintenance_margin"`
+	LiquidationMargin decimal.Decimal `gorm:"column:liquidation_margin"`
+	PositionID        sql.NullInt64   `gorm:"column:position_id"`
+	MatchRecordID     sql.NullInt64   `gorm:"column:match_record_id"`
+	CreatedAt         time.Time       `gorm:"column:created_at"`
+}
@@ -0,0 +1,40 @@
+package dbModels
+
+import (
+	"database/sql"
+	"time"
+)
+
+type MatchEventType int
+
+const (
+	MatchEventType_None       MatchEventType = iota
+	MatchEventType_Started                   // 開始撮合
+	MatchEventType_Filled                    // 撮合成交
+	MatchEventType_Failed                    // 撮合失敗或取消
+	MatchEventType_RolledBack                // 扣款後失敗並回滾
+)
+
+type OutboxStatus int
+
+const (
+	OutboxStatus_None    OutboxStatus = iota
+	OutboxStatus_Pending              // 待送出
+	OutboxStatus_Sent                 // 已送出
+)
+
+// MatchEventOutboxModel 與撮合紀錄在同一個transaction寫入, 由matchEventRelay送到rabbitmq
+type MatchEventOutboxModel struct {
+	ID            uint64         `gorm:"column:id; primary_key"`
+	EventID       string         `gorm:"column:event_id"`
+	EventType     MatchEventType `gorm:"column:event_type"`
+	OrderID       uint64         `gorm:"column:order_id"`
+	MatchRecordID uint64         `gorm:"column:match_record_id"`
+	Payload       string         `gorm:"column:payload"`
+	OutboxStatus  OutboxStatus   `gorm:"column:outbox_status"`
+	Attempts      int            `gorm:"column:attempts"`
+	LastError     string         `gorm:"column:last_error"`
+	SentAt        sql.NullTime   `gorm:"column:sent_at"`
+	CreatedAt     time.Time      `gorm:"column:created_at"`
+	UpdatedAt     time.Time      `gorm:"column:updated_at"`
+}
@@ -13,6 +13,7 @@ const (
 	TransactionType_NONE          TransactionType = iota
 	TransactionType_OpenPosition                  // 開倉
 	TransactionType_ClosePosition                 // 關倉
+	TransactionType_Liquidation                   // 強制平倉
 )
 
 type MatchStatus int
@@ -26,6 +27,28 @@ const (
 	MatchStatus_Rollbacked             // 回滾
 )
 
+type SagaStep int
+
+const (
+	SagaStep_None        SagaStep = iota
+	SagaStep_Created              // 已建立撮合紀錄
+	SagaStep_Transacted           // 已扣款
+	SagaStep_Matched              // 訂單已標記為撮合成功
+	SagaStep_Finished             // 訂單已完成
+	SagaStep_Compensated          // 已回滾扣款
+)
+
+type RetryOutcome int
+
+const (
+	RetryOutcome_None             RetryOutcome = iota
+	RetryOutcome_Succeeded                     // 成功
+	RetryOutcome_Exhausted                     // 重試次數用完
+	RetryOutcome_DeadlineExceeded              // 超過重試總時限
+	RetryOutcome_NonRetryable                  // 錯誤不可重試
+	RetryOutcome_Cancelled                     // 撮合被中止
+)
+
 type TradeType int
 
 const (
@@ -34,19 +57,76 @@ const (
 	TradeType_Sell
 )
 
+type OrderType int
+
+const (
+	OrderType_None   OrderType = iota
+	OrderType_Market           // 市價單
+	OrderType_Limit            // 限價單
+)
+
+type LegType int
+
+const (
+	LegType_None       LegType = iota
+	LegType_TakeProfit         // 停利附加單
+	LegType_StopLoss           // 停損附加單
+)
+
+type TimeInForce int
+
+const (
+	TimeInForce_None TimeInForce = iota
+	TimeInForce_GTC              // 取消前有效
+	TimeInForce_IOC              // 立即成交否則取消, 撮合不會部分成交, 即為全部成交否則取消
+	TimeInForce_FOK              // 全部成交否則取消, 與IOC相同, 撮合時以ErrUnsupportedTimeInForce拒絕
+	TimeInForce_DAY              // 當日有效, 交易所收盤時到期
+	TimeInForce_GTD              // 指定日期前有效
+)
+
+type ExpireOutcome int
+
+const (
+	ExpireOutcome_None    ExpireOutcome = iota
+	ExpireOutcome_Filled                // 期限內成交
+	ExpireOutcome_Killed                // 未立即成交而取消
+	ExpireOutcome_Expired               // 到期取消
+)
+
 type MatchRecordModel struct {
-	ID              uint64              `gorm:"column:id; primary_key"`
-	OrderID         uint64              `gorm:"column:order_id"`
-	MemberID        uint64              `gorm:"column:member_id"`
-	PositionID      sql.NullInt64       `gorm:"column:position_id"`
-	MatchStatus     MatchStatus         `gorm:"column:match_status"`
-	TransactionType TransactionType     `gorm:"column:transaction_type"`
-	ExchangeCode    string              `gorm:"column:exchange_code"`
-	ProductCode     string              `gorm:"column:product_code"`
-	TradeType       TradeType           `gorm:"column:trade_type"`
-	OpenPrice       decimal.NullDecimal `gorm:"column:open_price"`
-	ClosePrice      decimal.NullDecimal `gorm:"column:close_price"`
-	Amount          decimal.Decimal     `gorm:"column:amount"`
-	CreatedAt       time.Time           `gorm:"column:created_at"`
-	UpdatedAt       time.Time           `gorm:"column:updated_at"`
+	ID               uint64              `gorm:"column:id; primary_key"`
+	OrderID          uint64              `gorm:"column:order_id"`
+	MemberID         uint64              `gorm:"column:member_id"`
+	PositionID       sql.NullInt64       `gorm:"column:position_id"`
+	MatchStatus      MatchStatus         `gorm:"column:match_status"`
+	SagaStep         SagaStep            `gorm:"column:saga_step"`
+	TransactionType  TransactionType     `gorm:"column:transaction_type"`
+	ExchangeCode     string              `gorm:"column:exchange_code"`
+	ProductCode      string              `gorm:"column:product_code"`
+	TradeType        TradeType           `gorm:"column:trade_type"`
+	OrderType        OrderType           `gorm:"column:order_type"`
+	LimitPrice       decimal.NullDecimal `gorm:"column:limit_price"`
+	LegType          LegType             `gorm:"column:leg_type"`
+	LegOrderID       sql.NullInt64       `gorm:"column:leg_order_id"`
+	TimeInForce      TimeInForce         `gorm:"column:time_in_force"`
+	ExpireAt         sql.NullTime        `gorm:"column:expire_at"`
+	ExpireOutcome    ExpireOutcome       `gorm:"column:expire_outcome"`
+	OpenPrice        decimal.NullDecimal `gorm:"column:open_price"`
+	ClosePrice       decimal.NullDecimal `gorm:"column:close_price"`
+	Amount           decimal.Decimal     `gorm:"column:amount"`
+	PricingModel     PricingModel        `gorm:"column:pricing_model"`
+	QuoteAsk         decimal.NullDecimal `gorm:"column:quote_ask"`
+	QuoteBid         decimal.NullDecimal `gorm:"column:quote_bid"`
+	QuoteTime        sql.NullTime        `gorm:"column:quote_time"`
+	StaleQuote       bool                `gorm:"column:stale_quote"`
+	RetryCount       int                 `gorm:"column:retry_count"`
+	RetryOutcome     RetryOutcome        `gorm:"column:retry_outcome"`
+	RetryError       string              `gorm:"column:retry_error"`
+	TransactionID    sql.NullInt64       `gorm:"column:transaction_id"`
+	Fee              decimal.Decimal     `gorm:"column:fee"`
+	FeeTransactionID sql.NullInt64       `gorm:"column:fee_transaction_id"`
+	Leverage         decimal.Decimal     `gorm:"column:leverage"`
+	Margin           decimal.NullDecimal `gorm:"column:margin"`
+	CreatedAt        time.Time           `gorm:"column:created_at"`
+	UpdatedAt        time.Time           `gorm:"column:updated_at"`
 }
@@ -0,0 +1,40 @@
+package dbModels
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type PendingStatus int
+
+const (
+	PendingStatus_None       PendingStatus = iota
+	PendingStatus_Pending                  // 掛單中
+	PendingStatus_Filled                   // 已成交
+	PendingStatus_Failed                   // 失敗
+	PendingStatus_Cancelled                // 取消
+	PendingStatus_Processing               // 撮合中
+)
+
+type PendingOrderModel struct {
+	ID              uint64              `gorm:"column:id; primary_key"`
+	OrderID         uint64              `gorm:"column:order_id"`
+	MatchRecordID   uint64              `gorm:"column:match_record_id"`
+	MemberID        uint64              `gorm:"column:member_id"`
+	PositionID      sql.NullInt64       `gorm:"column:position_id"`
+	PendingStatus   PendingStatus       `gorm:"column:pending_status"`
+	TransactionType TransactionType     `gorm:"column:transaction_type"`
+	ExchangeCode    string              `gorm:"column:exchange_code"`
+	ProductCode     string              `gorm:"column:product_code"`
+	TradeType       TradeType           `gorm:"column:trade_type"`
+	OrderType       OrderType           `gorm:"column:order_type"`
+	LimitPrice      decimal.Decimal     `gorm:"column:limit_price"`
+	TimeInForce     TimeInForce         `gorm:"column:time_in_force"`
+	ExpireAt        sql.NullTime        `gorm:"column:expire_at"`
+	OpenPrice       decimal.NullDecimal `gorm:"column:open_price"`
+	Amount          decimal.Decimal     `gorm:"column:amount"`
+	CreatedAt       time.Time           `gorm:"column:created_at"`
+	UpdatedAt       time.Time           `gorm:"column:updated_at"`
+}
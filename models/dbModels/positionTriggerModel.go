@@ -0,0 +1,56 @@
+package dbModels
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type TriggerType int
+
+const (
+	TriggerType_None         TriggerType = iota
+	TriggerType_StopLoss                 // 停損
+	TriggerType_TakeProfit               // 停利
+	TriggerType_TrailingStop             // 移動停損
+)
+
+type TrailMode int
+
+const (
+	TrailMode_None    TrailMode = iota
+	TrailMode_Price             // 固定價差
+	TrailMode_Percent           // 百分比
+)
+
+type TriggerStatus int
+
+const (
+	TriggerStatus_None      TriggerStatus = iota
+	TriggerStatus_Active                  // 監控中
+	TriggerStatus_Triggered               // 已觸發並平倉
+	TriggerStatus_Cancelled               // 取消
+)
+
+type PositionTriggerModel struct {
+	ID             uint64              `gorm:"column:id; primary_key"`
+	PositionID     uint64              `gorm:"column:position_id"`
+	MemberID       uint64              `gorm:"column:member_id"`
+	ExchangeCode   string              `gorm:"column:exchange_code"`
+	ProductCode    string              `gorm:"column:product_code"`
+	TradeType      TradeType           `gorm:"column:trade_type"`
+	TriggerType    TriggerType         `gorm:"column:trigger_type"`
+	TriggerStatus  TriggerStatus       `gorm:"column:trigger_status"`
+	TriggerPrice   decimal.Decimal     `gorm:"column:trigger_price"`
+	TrailMode      TrailMode           `gorm:"column:trail_mode"`
+	TrailValue     decimal.NullDecimal `gorm:"column:trail_value"`
+	ParentOrderID  sql.NullInt64       `gorm:"column:parent_order_id"`
+	MatchRecordID  sql.NullInt64       `gorm:"column:match_record_id"`
+	TriggeredPrice decimal.NullDecimal `gorm:"column:triggered_price"`
+	CloseOrderID   sql.NullInt64       `gorm:"column:close_order_id"`
+	ClosePrice     decimal.NullDecimal `gorm:"column:close_price"`
+	TriggeredAt    sql.NullTime        `gorm:"column:triggered_at"`
+	CreatedAt      time.Time           `gorm:"column:created_at"`
+	UpdatedAt      time.Time           `gorm:"column:updated_at"`
+}
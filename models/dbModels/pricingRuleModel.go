@@ -0,0 +1,32 @@
+package dbModels
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type PricingModel int
+
+const (
+	PricingModel_None         PricingModel = iota
+	PricingModel_Touch                     // 對手價, 買用ask賣用bid
+	PricingModel_Mid                       // 中間價
+	PricingModel_FixedBps                  // 對手價加上固定滑價
+	PricingModel_MarketImpact              // 對手價加上依數量增加的滑價
+)
+
+type PricingRuleModel struct {
+	ID              uint64              `gorm:"column:id; primary_key"`
+	ExchangeCode    string              `gorm:"column:exchange_code"`
+	ProductCode     string              `gorm:"column:product_code"`
+	PricingModel    PricingModel        `gorm:"column:pricing_model"`
+	SlippageBps     decimal.Decimal     `gorm:"column:slippage_bps"`
+	ImpactBps       decimal.Decimal     `gorm:"column:impact_bps"`
+	ReferenceAmount decimal.NullDecimal `gorm:"column:reference_amount"`
+	MaxSlippageBps  decimal.NullDecimal `gorm:"column:max_slippage_bps"`
+	MaxQuoteAge     sql.NullInt64       `gorm:"column:max_quote_age"`
+	CreatedAt       time.Time           `gorm:"column:created_at"`
+	UpdatedAt       time.Time           `gorm:"column:updated_at"`
+}
@@ -0,0 +1,43 @@
+package dbModels
+
+import (
+	"database/sql"
+	"time"
+)
+
+type DiscrepancyType int
+
+const (
+	DiscrepancyType_None                  DiscrepancyType = iota
+	DiscrepancyType_OrderNotFound                         // 查無訂單
+	DiscrepancyType_OrderStatusMismatch                   // 訂單狀態與撮合紀錄不符
+	DiscrepancyType_TransactionNotFound                   // 查無撮合紀錄的錢包交易
+	DiscrepancyType_TransactionNotSuccess                 // 撮合完成但錢包交易未成功
+	DiscrepancyType_MissingRollback                       // 撮合失敗但扣款未回滾
+	DiscrepancyType_OrphanTransaction                     // 錢包交易沒有對應的撮合紀錄
+)
+
+type RepairStatus int
+
+const (
+	RepairStatus_None         RepairStatus = iota
+	RepairStatus_Manual                    // 待人工處理
+	RepairStatus_Repaired                  // 已自動修復
+	RepairStatus_RepairFailed              // 自動修復失敗
+)
+
+type ReconciliationDiscrepancyModel struct {
+	ID                uint64          `gorm:"column:id; primary_key"`
+	DiscrepancyType   DiscrepancyType `gorm:"column:discrepancy_type"`
+	OrderID           uint64          `gorm:"column:order_id"`
+	TransactionID     uint64          `gorm:"column:transaction_id"`
+	MatchRecordID     sql.NullInt64   `gorm:"column:match_record_id"`
+	MemberID          uint64          `gorm:"column:member_id"`
+	MatchStatus       sql.NullInt32   `gorm:"column:match_status"`
+	OrderStatus       sql.NullInt32   `gorm:"column:order_status"`
+	TransactionStatus sql.NullInt32   `gorm:"column:transaction_status"`
+	RepairStatus      RepairStatus    `gorm:"column:repair_status"`
+	Remark            string          `gorm:"column:remark"`
+	CreatedAt         time.Time       `gorm:"column:created_at"`
+	UpdatedAt         time.Time       `gorm:"column:updated_at"`
+}
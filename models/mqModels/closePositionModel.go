@@ -0,0 +1,19 @@
+package mqModels
+
+import (
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/paper-trade-chatbot/be-pubsub/order/closePosition/rabbitmq"
+	"github.com/shopspring/decimal"
+)
+
+// ClosePositionModel 關倉訊息, 在be-pubsub的欄位外加上be-match自己需要的欄位
+type ClosePositionModel struct {
+	rabbitmq.ClosePositionModel
+	OrderType  dbModels.OrderType  `json:"orderType"`  // 未帶值時視為市價單
+	LimitPrice decimal.NullDecimal `json:"limitPrice"` // 限價單的限價
+
+	TimeInForce dbModels.TimeInForce `json:"timeInForce"` // 未帶值時視為GTC
+	ExpireAt    *int64               `json:"expireAt"`    // GTD的到期時間, unix秒
+
+	TransactionType dbModels.TransactionType `json:"-"` // 由be-match主動平倉時帶入, 未帶值時視為一般關倉
+}
@@ -0,0 +1,47 @@
+package mqModels
+
+import (
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/shopspring/decimal"
+)
+
+// MatchEventModel 撮合事件的共同欄位, 讓其他服務不需輪詢訂單服務即可得知撮合結果
+type MatchEventModel struct {
+	EventID          string                   `valid:"required" json:"eventID"` // 至少送達一次, 消費端以此去除重複
+	OrderID          uint64                   `valid:"required" json:"orderID"`
+	MatchRecordID    uint64                   `json:"matchRecordID"` // 撮合紀錄建立前失敗時為0
+	MemberID         uint64                   `valid:"required" json:"memberID"`
+	TransactionType  dbModels.TransactionType `json:"transactionType"`
+	ExchangeCode     string                   `json:"exchangeCode"`
+	ProductCode      string                   `json:"productCode"`
+	TradeType        dbModels.TradeType       `json:"tradeType"`
+	PositionID       *uint64                  `json:"positionID"`       // 開倉成交後才有值
+	Price            decimal.NullDecimal      `json:"price"`            // 開倉為開倉價, 關倉為平倉價
+	Amount           decimal.Decimal          `json:"amount"`           // 交易數量
+	Fee              decimal.Decimal          `json:"fee"`              // 手續費
+	TransactionID    *uint64                  `json:"transactionID"`    // 錢包交易id
+	FeeTransactionID *uint64                  `json:"feeTransactionID"` // 手續費的錢包交易id
+	FailCode         *uint64                  `json:"failCode"`         // 失敗及回滾時的錯誤碼
+	FailReason       *string                  `json:"failReason"`       // 失敗及回滾時的錯誤訊息
+	OccurredAt       int64                    `json:"occurredAt"`       // 事件發生時間, unix秒
+}
+
+// MatchStartedModel 開始撮合
+type MatchStartedModel struct {
+	MatchEventModel
+}
+
+// MatchFilledModel 撮合成交
+type MatchFilledModel struct {
+	MatchEventModel
+}
+
+// MatchFailedModel 撮合失敗或取消, 沒有扣款
+type MatchFailedModel struct {
+	MatchEventModel
+}
+
+// MatchRolledBackModel 扣款後撮合失敗, 已回滾錢包交易
+type MatchRolledBackModel struct {
+	MatchEventModel
+}
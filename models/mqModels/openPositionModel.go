@@ -0,0 +1,31 @@
+package mqModels
+
+import (
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/paper-trade-chatbot/be-pubsub/order/openPosition/rabbitmq"
+	"github.com/shopspring/decimal"
+)
+
+// BracketModel 開倉成交後附加的停利及停損單, 其中一張成交時另一張自動取消
+type BracketModel struct {
+	TakeProfitPrice decimal.Decimal `json:"takeProfitPrice"` // 停利限價
+	StopLossPrice   decimal.Decimal `json:"stopLossPrice"`   // 停損觸發價
+}
+
+// OpenPositionModel 開倉訊息, 在be-pubsub的欄位外加上be-match自己需要的欄位
+type OpenPositionModel struct {
+	rabbitmq.OpenPositionModel
+	OrderType  dbModels.OrderType  `json:"orderType"`  // 未帶值時視為市價單
+	LimitPrice decimal.NullDecimal `json:"limitPrice"` // 限價單的限價
+
+	TimeInForce dbModels.TimeInForce `json:"timeInForce"` // 未帶值時視為GTC
+	ExpireAt    *int64               `json:"expireAt"`    // GTD的到期時間, unix秒
+
+	StopLossPrice   decimal.NullDecimal `json:"stopLossPrice"`   // 成交後掛在倉位上的停損價
+	TakeProfitPrice decimal.NullDecimal `json:"takeProfitPrice"` // 成交後掛在倉位上的停利價
+
+	TrailMode  dbModels.TrailMode  `json:"trailMode"`  // 移動停損的計算方式
+	TrailValue decimal.NullDecimal `json:"trailValue"` // 移動停損的價差, 百分比時5代表5%
+
+	Bracket *BracketModel `json:"bracket"` // 附加的停利停損二擇一單
+}
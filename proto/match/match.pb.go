@@ -0,0 +1,1686 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        (unknown)
+// source: match/match.proto
+
+package match
+
+import (
+	general "github.com/paper-trade-chatbot/be-proto/general"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type MatchStatus int32
+
+const (
+	MatchStatus_MatchStatus_None       MatchStatus = 0
+	MatchStatus_MatchStatus_Pending    MatchStatus = 1
+	MatchStatus_MatchStatus_Failed     MatchStatus = 2
+	MatchStatus_MatchStatus_Finished   MatchStatus = 3
+	MatchStatus_MatchStatus_Cancelled  MatchStatus = 4
+	MatchStatus_MatchStatus_Rollbacked MatchStatus = 5
+)
+
+// Enum value maps for MatchStatus.
+var (
+	MatchStatus_name = map[int32]string{
+		0: "MatchStatus_None",
+		1: "MatchStatus_Pending",
+		2: "MatchStatus_Failed",
+		3: "MatchStatus_Finished",
+		4: "MatchStatus_Cancelled",
+		5: "MatchStatus_Rollbacked",
+	}
+	MatchStatus_value = map[string]int32{
+		"MatchStatus_None":       0,
+		"MatchStatus_Pending":    1,
+		"MatchStatus_Failed":     2,
+		"MatchStatus_Finished":   3,
+		"MatchStatus_Cancelled":  4,
+		"MatchStatus_Rollbacked": 5,
+	}
+)
+
+func (x MatchStatus) Enum() *MatchStatus {
+	p := new(MatchStatus)
+	*p = x
+	return p
+}
+
+func (x MatchStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MatchStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_match_match_proto_enumTypes[0].Descriptor()
+}
+
+func (MatchStatus) Type() protoreflect.EnumType {
+	return &file_match_match_proto_enumTypes[0]
+}
+
+func (x MatchStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MatchStatus.Descriptor instead.
+func (MatchStatus) EnumDescriptor() ([]byte, []int) {
+	return file_match_match_proto_rawDescGZIP(), []int{0}
+}
+
+type TransactionType int32
+
+const (
+	TransactionType_TransactionType_None          TransactionType = 0
+	TransactionType_TransactionType_OpenPosition  TransactionType = 1
+	TransactionType_TransactionType_ClosePosition TransactionType = 2
+	TransactionType_TransactionType_Liquidation   TransactionType = 3
+)
+
+// Enum value maps for TransactionType.
+var (
+	TransactionType_name = map[int32]string{
+		0: "TransactionType_None",
+		1: "TransactionType_OpenPosition",
+		2: "TransactionType_ClosePosition",
+		3: "TransactionType_Liquidation",
+	}
+	TransactionType_value = map[string]int32{
+		"TransactionType_None":          0,
+		"TransactionType_OpenPosition":  1,
+		"TransactionType_ClosePosition": 2,
+		"TransactionType_Liquidation":   3,
+	}
+)
+
+func (x TransactionType) Enum() *TransactionType {
+	p := new(TransactionType)
+	*p = x
+	return p
+}
+
+func (x TransactionType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TransactionType) Descriptor() protoreflect.EnumDescriptor {
+	return file_match_match_proto_enumTypes[1].Descriptor()
+}
+
+func (TransactionType) Type() protoreflect.EnumType {
+	return &file_match_match_proto_enumTypes[1]
+}
+
+func (x TransactionType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TransactionType.Descriptor instead.
+func (TransactionType) EnumDescriptor() ([]byte, []int) {
+	return file_match_match_proto_rawDescGZIP(), []int{1}
+}
+
+type TradeType int32
+
+const (
+	TradeType_TradeType_None TradeType = 0
+	TradeType_TradeType_Buy  TradeType = 1
+	TradeType_TradeType_Sell TradeType = 2
+)
+
+// Enum value maps for TradeType.
+var (
+	TradeType_name = map[int32]string{
+		0: "TradeType_None",
+		1: "TradeType_Buy",
+		2: "TradeType_Sell",
+	}
+	TradeType_value = map[string]int32{
+		"TradeType_None": 0,
+		"TradeType_Buy":  1,
+		"TradeType_Sell": 2,
+	}
+)
+
+func (x TradeType) Enum() *TradeType {
+	p := new(TradeType)
+	*p = x
+	return p
+}
+
+func (x TradeType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TradeType) Descriptor() protoreflect.EnumDescriptor {
+	return file_match_match_proto_enumTypes[2].Descriptor()
+}
+
+func (TradeType) Type() protoreflect.EnumType {
+	return &file_match_match_proto_enumTypes[2]
+}
+
+func (x TradeType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TradeType.Descriptor instead.
+func (TradeType) EnumDescriptor() ([]byte, []int) {
+	return file_match_match_proto_rawDescGZIP(), []int{2}
+}
+
+type OrderType int32
+
+const (
+	OrderType_OrderType_None   OrderType = 0
+	OrderType_OrderType_Market OrderType = 1
+	OrderType_OrderType_Limit  OrderType = 2
+)
+
+// Enum value maps for OrderType.
+var (
+	OrderType_name = map[int32]string{
+		0: "OrderType_None",
+		1: "OrderType_Market",
+		2: "OrderType_Limit",
+	}
+	OrderType_value = map[string]int32{
+		"OrderType_None":   0,
+		"OrderType_Market": 1,
+		"OrderType_Limit":  2,
+	}
+)
+
+func (x OrderType) Enum() *OrderType {
+	p := new(OrderType)
+	*p = x
+	return p
+}
+
+func (x OrderType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderType) Descriptor() protoreflect.EnumDescriptor {
+	return file_match_match_proto_enumTypes[3].Descriptor()
+}
+
+func (OrderType) Type() protoreflect.EnumType {
+	return &file_match_match_proto_enumTypes[3]
+}
+
+func (x OrderType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderType.Descriptor instead.
+func (OrderType) EnumDescriptor() ([]byte, []int) {
+	return file_match_match_proto_rawDescGZIP(), []int{3}
+}
+
+type LegType int32
+
+const (
+	LegType_LegType_None       LegType = 0
+	LegType_LegType_TakeProfit LegType = 1
+	LegType_LegType_StopLoss   LegType = 2
+)
+
+// Enum value maps for LegType.
+var (
+	LegType_name = map[int32]string{
+		0: "LegType_None",
+		1: "LegType_TakeProfit",
+		2: "LegType_StopLoss",
+	}
+	LegType_value = map[string]int32{
+		"LegType_None":       0,
+		"LegType_TakeProfit": 1,
+		"LegType_StopLoss":   2,
+	}
+)
+
+func (x LegType) Enum() *LegType {
+	p := new(LegType)
+	*p = x
+	return p
+}
+
+func (x LegType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LegType) Descriptor() protoreflect.EnumDescriptor {
+	return file_match_match_proto_enumTypes[4].Descriptor()
+}
+
+func (LegType) Type() protoreflect.EnumType {
+	return &file_match_match_proto_enumTypes[4]
+}
+
+func (x LegType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LegType.Descriptor instead.
+func (LegType) EnumDescriptor() ([]byte, []int) {
+	return file_match_match_proto_rawDescGZIP(), []int{4}
+}
+
+type TimeInForce int32
+
+const (
+	TimeInForce_TimeInForce_None TimeInForce = 0
+	TimeInForce_TimeInForce_GTC  TimeInForce = 1
+	TimeInForce_TimeInForce_IOC  TimeInForce = 2
+	TimeInForce_TimeInForce_FOK  TimeInForce = 3
+	TimeInForce_TimeInForce_DAY  TimeInForce = 4
+	TimeInForce_TimeInForce_GTD  TimeInForce = 5
+)
+
+// Enum value maps for TimeInForce.
+var (
+	TimeInForce_name = map[int32]string{
+		0: "TimeInForce_None",
+		1: "TimeInForce_GTC",
+		2: "TimeInForce_IOC",
+		3: "TimeInForce_FOK",
+		4: "TimeInForce_DAY",
+		5: "TimeInForce_GTD",
+	}
+	TimeInForce_value = map[string]int32{
+		"TimeInForce_None": 0,
+		"TimeInForce_GTC":  1,
+		"TimeInForce_IOC":  2,
+		"TimeInForce_FOK":  3,
+		"TimeInForce_DAY":  4,
+		"TimeInForce_GTD":  5,
+	}
+)
+
+func (x TimeInForce) Enum() *TimeInForce {
+	p := new(TimeInForce)
+	*p = x
+	return p
+}
+
+func (x TimeInForce) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TimeInForce) Descriptor() protoreflect.EnumDescriptor {
+	return file_match_match_proto_enumTypes[5].Descriptor()
+}
+
+func (TimeInForce) Type() protoreflect.EnumType {
+	return &file_match_match_proto_enumTypes[5]
+}
+
+func (x TimeInForce) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TimeInForce.Descriptor instead.
+func (TimeInForce) EnumDescriptor() ([]byte, []int) {
+	return file_match_match_proto_rawDescGZIP(), []int{5}
+}
+
+type ExpireOutcome int32
+
+const (
+	ExpireOutcome_ExpireOutcome_None    ExpireOutcome = 0
+	ExpireOutcome_ExpireOutcome_Filled  ExpireOutcome = 1
+	ExpireOutcome_ExpireOutcome_Killed  ExpireOutcome = 2
+	ExpireOutcome_ExpireOutcome_Expired ExpireOutcome = 3
+)
+
+// Enum value maps for ExpireOutcome.
+var (
+	ExpireOutcome_name = map[int32]string{
+		0: "ExpireOutcome_None",
+		1: "ExpireOutcome_Filled",
+		2: "ExpireOutcome_Killed",
+		3: "ExpireOutcome_Expired",
+	}
+	ExpireOutcome_value = map[string]int32{
+		"ExpireOutcome_None":    0,
+		"ExpireOutcome_Filled":  1,
+		"ExpireOutcome_Killed":  2,
+		"ExpireOutcome_Expired": 3,
+	}
+)
+
+func (x ExpireOutcome) Enum() *ExpireOutcome {
+	p := new(ExpireOutcome)
+	*p = x
+	return p
+}
+
+func (x ExpireOutcome) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ExpireOutcome) Descriptor() protoreflect.EnumDescriptor {
+	return file_match_match_proto_enumTypes[6].Descriptor()
+}
+
+func (ExpireOutcome) Type() protoreflect.EnumType {
+	return &file_match_match_proto_enumTypes[6]
+}
+
+func (x ExpireOutcome) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ExpireOutcome.Descriptor instead.
+func (ExpireOutcome) EnumDescriptor() ([]byte, []int) {
+	return file_match_match_proto_rawDescGZIP(), []int{6}
+}
+
+type PricingModel int32
+
+const (
+	PricingModel_PricingModel_None         PricingModel = 0
+	PricingModel_PricingModel_Touch        PricingModel = 1
+	PricingModel_PricingModel_Mid          PricingModel = 2
+	PricingModel_PricingModel_FixedBps     PricingModel = 3
+	PricingModel_PricingModel_MarketImpact PricingModel = 4
+)
+
+// Enum value maps for PricingModel.
+var (
+	PricingModel_name = map[int32]string{
+		0: "PricingModel_None",
+		1: "PricingModel_Touch",
+		2: "PricingModel_Mid",
+		3: "PricingModel_FixedBps",
+		4: "PricingModel_MarketImpact",
+	}
+	PricingModel_value = map[string]int32{
+		"PricingModel_None":         0,
+		"PricingModel_Touch":        1,
+		"PricingModel_Mid":          2,
+		"PricingModel_FixedBps":     3,
+		"PricingModel_MarketImpact": 4,
+	}
+)
+
+func (x PricingModel) Enum() *PricingModel {
+	p := new(PricingModel)
+	*p = x
+	return p
+}
+
+func (x PricingModel) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PricingModel) Descriptor() protoreflect.EnumDescriptor {
+	return file_match_match_proto_enumTypes[7].Descriptor()
+}
+
+func (PricingModel) Type() protoreflect.EnumType {
+	return &file_match_match_proto_enumTypes[7]
+}
+
+func (x PricingModel) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PricingModel.Descriptor instead.
+func (PricingModel) EnumDescriptor() ([]byte, []int) {
+	return file_match_match_proto_rawDescGZIP(), []int{7}
+}
+
+type RetryOutcome int32
+
+const (
+	RetryOutcome_RetryOutcome_None             RetryOutcome = 0
+	RetryOutcome_RetryOutcome_Succeeded        RetryOutcome = 1
+	RetryOutcome_RetryOutcome_Exhausted        RetryOutcome = 2
+	RetryOutcome_RetryOutcome_DeadlineExceeded RetryOutcome = 3
+	RetryOutcome_RetryOutcome_NonRetryable     RetryOutcome = 4
+	RetryOutcome_RetryOutcome_Cancelled        RetryOutcome = 5
+)
+
+// Enum value maps for RetryOutcome.
+var (
+	RetryOutcome_name = map[int32]string{
+		0: "RetryOutcome_None",
+		1: "RetryOutcome_Succeeded",
+		2: "RetryOutcome_Exhausted",
+		3: "RetryOutcome_DeadlineExceeded",
+		4: "RetryOutcome_NonRetryable",
+		5: "RetryOutcome_Cancelled",
+	}
+	RetryOutcome_value = map[string]int32{
+		"RetryOutcome_None":             0,
+		"RetryOutcome_Succeeded":        1,
+		"RetryOutcome_Exhausted":        2,
+		"RetryOutcome_DeadlineExceeded": 3,
+		"RetryOutcome_NonRetryable":     4,
+		"RetryOutcome_Cancelled":        5,
+	}
+)
+
+func (x RetryOutcome) Enum() *RetryOutcome {
+	p := new(RetryOutcome)
+	*p = x
+	return p
+}
+
+func (x RetryOutcome) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RetryOutcome) Descriptor() protoreflect.EnumDescriptor {
+	return file_match_match_proto_enumTypes[8].Descriptor()
+}
+
+func (RetryOutcome) Type() protoreflect.EnumType {
+	return &file_match_match_proto_enumTypes[8]
+}
+
+func (x RetryOutcome) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RetryOutcome.Descriptor instead.
+func (RetryOutcome) EnumDescriptor() ([]byte, []int) {
+	return file_match_match_proto_rawDescGZIP(), []int{8}
+}
+
+type SagaStep int32
+
+const (
+	SagaStep_SagaStep_None        SagaStep = 0
+	SagaStep_SagaStep_Created     SagaStep = 1
+	SagaStep_SagaStep_Transacted  SagaStep = 2
+	SagaStep_SagaStep_Matched     SagaStep = 3
+	SagaStep_SagaStep_Finished    SagaStep = 4
+	SagaStep_SagaStep_Compensated SagaStep = 5
+)
+
+// Enum value maps for SagaStep.
+var (
+	SagaStep_name = map[int32]string{
+		0: "SagaStep_None",
+		1: "SagaStep_Created",
+		2: "SagaStep_Transacted",
+		3: "SagaStep_Matched",
+		4: "SagaStep_Finished",
+		5: "SagaStep_Compensated",
+	}
+	SagaStep_value = map[string]int32{
+		"SagaStep_None":        0,
+		"SagaStep_Created":     1,
+		"SagaStep_Transacted":  2,
+		"SagaStep_Matched":     3,
+		"SagaStep_Finished":    4,
+		"SagaStep_Compensated": 5,
+	}
+)
+
+func (x SagaStep) Enum() *SagaStep {
+	p := new(SagaStep)
+	*p = x
+	return p
+}
+
+func (x SagaStep) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SagaStep) Descriptor() protoreflect.EnumDescriptor {
+	return file_match_match_proto_enumTypes[9].Descriptor()
+}
+
+func (SagaStep) Type() protoreflect.EnumType {
+	return &file_match_match_proto_enumTypes[9]
+}
+
+func (x SagaStep) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SagaStep.Descriptor instead.
+func (SagaStep) EnumDescriptor() ([]byte, []int) {
+	return file_match_match_proto_rawDescGZIP(), []int{9}
+}
+
+type MatchRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id               uint64          `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderID          uint64          `protobuf:"varint,2,opt,name=orderID,proto3" json:"orderID,omitempty"`
+	MemberID         uint64          `protobuf:"varint,3,opt,name=memberID,proto3" json:"memberID,omitempty"`
+	PositionID       *uint64         `protobuf:"varint,4,opt,name=positionID,proto3,oneof" json:"positionID,omitempty"`
+	MatchStatus      MatchStatus     `protobuf:"varint,5,opt,name=matchStatus,proto3,enum=match.MatchStatus" json:"matchStatus,omitempty"`
+	TransactionType  TransactionType `protobuf:"varint,6,opt,name=transactionType,proto3,enum=match.TransactionType" json:"transactionType,omitempty"`
+	ExchangeCode     string          `protobuf:"bytes,7,opt,name=exchangeCode,proto3" json:"exchangeCode,omitempty"`
+	ProductCode      string          `protobuf:"bytes,8,opt,name=productCode,proto3" json:"productCode,omitempty"`
+	TradeType        TradeType       `protobuf:"varint,9,opt,name=tradeType,proto3,enum=match.TradeType" json:"tradeType,omitempty"`
+	OrderType        OrderType       `protobuf:"varint,10,opt,name=orderType,proto3,enum=match.OrderType" json:"orderType,omitempty"`
+	LimitPrice       *string         `protobuf:"bytes,11,opt,name=limitPrice,proto3,oneof" json:"limitPrice,omitempty"`
+	LegType          LegType         `protobuf:"varint,12,opt,name=legType,proto3,enum=match.LegType" json:"legType,omitempty"`
+	LegOrderID       *uint64         `protobuf:"varint,13,opt,name=legOrderID,proto3,oneof" json:"legOrderID,omitempty"`
+	TimeInForce      TimeInForce     `protobuf:"varint,14,opt,name=timeInForce,proto3,enum=match.TimeInForce" json:"timeInForce,omitempty"`
+	ExpireAt         *int64          `protobuf:"varint,15,opt,name=expireAt,proto3,oneof" json:"expireAt,omitempty"`
+	OpenPrice        *string         `protobuf:"bytes,16,opt,name=openPrice,proto3,oneof" json:"openPrice,omitempty"`
+	ClosePrice       *string         `protobuf:"bytes,17,opt,name=closePrice,proto3,oneof" json:"closePrice,omitempty"`
+	Amount           string          `protobuf:"bytes,18,opt,name=amount,proto3" json:"amount,omitempty"`
+	TransactionID    *uint64         `protobuf:"varint,19,opt,name=transactionID,proto3,oneof" json:"transactionID,omitempty"`
+	Fee              string          `protobuf:"bytes,20,opt,name=fee,proto3" json:"fee,omitempty"`
+	FeeTransactionID *uint64         `protobuf:"varint,21,opt,name=feeTransactionID,proto3,oneof" json:"feeTransactionID,omitempty"`
+	Leverage         string          `protobuf:"bytes,22,opt,name=leverage,proto3" json:"leverage,omitempty"`
+	Margin           *string         `protobuf:"bytes,23,opt,name=margin,proto3,oneof" json:"margin,omitempty"`
+	CreatedAt        int64           `protobuf:"varint,24,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	UpdatedAt        int64           `protobuf:"varint,25,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`
+	ExpireOutcome    ExpireOutcome   `protobuf:"varint,26,opt,name=expireOutcome,proto3,enum=match.ExpireOutcome" json:"expireOutcome,omitempty"`
+	PricingModel     PricingModel    `protobuf:"varint,27,opt,name=pricingModel,proto3,enum=match.PricingModel" json:"pricingModel,omitempty"`
+	QuoteAsk         *string         `protobuf:"bytes,28,opt,name=quoteAsk,proto3,oneof" json:"quoteAsk,omitempty"`
+	QuoteBid         *string         `protobuf:"bytes,29,opt,name=quoteBid,proto3,oneof" json:"quoteBid,omitempty"`
+	QuoteTime        *int64          `protobuf:"varint,30,opt,name=quoteTime,proto3,oneof" json:"quoteTime,omitempty"`
+	StaleQuote       bool            `protobuf:"varint,31,opt,name=staleQuote,proto3" json:"staleQuote,omitempty"`
+	RetryCount       int32           `protobuf:"varint,32,opt,name=retryCount,proto3" json:"retryCount,omitempty"`
+	RetryOutcome     RetryOutcome    `protobuf:"varint,33,opt,name=retryOutcome,proto3,enum=match.RetryOutcome" json:"retryOutcome,omitempty"`
+	RetryError       string          `protobuf:"bytes,34,opt,name=retryError,proto3" json:"retryError,omitempty"`
+	SagaStep         SagaStep        `protobuf:"varint,35,opt,name=sagaStep,proto3,enum=match.SagaStep" json:"sagaStep,omitempty"`
+}
+
+func (x *MatchRecord) Reset() {
+	*x = MatchRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_match_match_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MatchRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchRecord) ProtoMessage() {}
+
+func (x *MatchRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_match_match_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchRecord.ProtoReflect.Descriptor instead.
+func (*MatchRecord) Descriptor() ([]byte, []int) {
+	return file_match_match_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *MatchRecord) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *MatchRecord) GetOrderID() uint64 {
+	if x != nil {
+		return x.OrderID
+	}
+	return 0
+}
+
+func (x *MatchRecord) GetMemberID() uint64 {
+	if x != nil {
+		return x.MemberID
+	}
+	return 0
+}
+
+func (x *MatchRecord) GetPositionID() uint64 {
+	if x != nil && x.PositionID != nil {
+		return *x.PositionID
+	}
+	return 0
+}
+
+func (x *MatchRecord) GetMatchStatus() MatchStatus {
+	if x != nil {
+		return x.MatchStatus
+	}
+	return MatchStatus_MatchStatus_None
+}
+
+func (x *MatchRecord) GetTransactionType() TransactionType {
+	if x != nil {
+		return x.TransactionType
+	}
+	return TransactionType_TransactionType_None
+}
+
+func (x *MatchRecord) GetExchangeCode() string {
+	if x != nil {
+		return x.ExchangeCode
+	}
+	return ""
+}
+
+func (x *MatchRecord) GetProductCode() string {
+	if x != nil {
+		return x.ProductCode
+	}
+	return ""
+}
+
+func (x *MatchRecord) GetTradeType() TradeType {
+	if x != nil {
+		return x.TradeType
+	}
+	return TradeType_TradeType_None
+}
+
+func (x *MatchRecord) GetOrderType() OrderType {
+	if x != nil {
+		return x.OrderType
+	}
+	return OrderType_OrderType_None
+}
+
+func (x *MatchRecord) GetLimitPrice() string {
+	if x != nil && x.LimitPrice != nil {
+		return *x.LimitPrice
+	}
+	return ""
+}
+
+func (x *MatchRecord) GetLegType() LegType {
+	if x != nil {
+		return x.LegType
+	}
+	return LegType_LegType_None
+}
+
+func (x *MatchRecord) GetLegOrderID() uint64 {
+	if x != nil && x.LegOrderID != nil {
+		return *x.LegOrderID
+	}
+	return 0
+}
+
+func (x *MatchRecord) GetTimeInForce() TimeInForce {
+	if x != nil {
+		return x.TimeInForce
+	}
+	return TimeInForce_TimeInForce_None
+}
+
+func (x *MatchRecord) GetExpireAt() int64 {
+	if x != nil && x.ExpireAt != nil {
+		return *x.ExpireAt
+	}
+	return 0
+}
+
+func (x *MatchRecord) GetOpenPrice() string {
+	if x != nil && x.OpenPrice != nil {
+		return *x.OpenPrice
+	}
+	return ""
+}
+
+func (x *MatchRecord) GetClosePrice() string {
+	if x != nil && x.ClosePrice != nil {
+		return *x.ClosePrice
+	}
+	return ""
+}
+
+func (x *MatchRecord) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *MatchRecord) GetTransactionID() uint64 {
+	if x != nil && x.TransactionID != nil {
+		return *x.TransactionID
+	}
+	return 0
+}
+
+func (x *MatchRecord) GetFee() string {
+	if x != nil {
+		return x.Fee
+	}
+	return ""
+}
+
+func (x *MatchRecord) GetFeeTransactionID() uint64 {
+	if x != nil && x.FeeTransactionID != nil {
+		return *x.FeeTransactionID
+	}
+	return 0
+}
+
+func (x *MatchRecord) GetLeverage() string {
+	if x != nil {
+		return x.Leverage
+	}
+	return ""
+}
+
+func (x *MatchRecord) GetMargin() string {
+	if x != nil && x.Margin != nil {
+		return *x.Margin
+	}
+	return ""
+}
+
+func (x *MatchRecord) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *MatchRecord) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *MatchRecord) GetExpireOutcome() ExpireOutcome {
+	if x != nil {
+		return x.ExpireOutcome
+	}
+	return ExpireOutcome_ExpireOutcome_None
+}
+
+func (x *MatchRecord) GetPricingModel() PricingModel {
+	if x != nil {
+		return x.PricingModel
+	}
+	return PricingModel_PricingModel_None
+}
+
+func (x *MatchRecord) GetQuoteAsk() string {
+	if x != nil && x.QuoteAsk != nil {
+		return *x.QuoteAsk
+	}
+	return ""
+}
+
+func (x *MatchRecord) GetQuoteBid() string {
+	if x != nil && x.QuoteBid != nil {
+		return *x.QuoteBid
+	}
+	return ""
+}
+
+func (x *MatchRecord) GetQuoteTime() int64 {
+	if x != nil && x.QuoteTime != nil {
+		return *x.QuoteTime
+	}
+	return 0
+}
+
+func (x *MatchRecord) GetStaleQuote() bool {
+	if x != nil {
+		return x.StaleQuote
+	}
+	return false
+}
+
+func (x *MatchRecord) GetRetryCount() int32 {
+	if x != nil {
+		return x.RetryCount
+	}
+	return 0
+}
+
+func (x *MatchRecord) GetRetryOutcome() RetryOutcome {
+	if x != nil {
+		return x.RetryOutcome
+	}
+	return RetryOutcome_RetryOutcome_None
+}
+
+func (x *MatchRecord) GetRetryError() string {
+	if x != nil {
+		return x.RetryError
+	}
+	return ""
+}
+
+func (x *MatchRecord) GetSagaStep() SagaStep {
+	if x != nil {
+		return x.SagaStep
+	}
+	return SagaStep_SagaStep_None
+}
+
+type OrderIDTransactionType struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OrderID         uint64          `protobuf:"varint,1,opt,name=orderID,proto3" json:"orderID,omitempty"`
+	TransactionType TransactionType `protobuf:"varint,2,opt,name=transactionType,proto3,enum=match.TransactionType" json:"transactionType,omitempty"`
+}
+
+func (x *OrderIDTransactionType) Reset() {
+	*x = OrderIDTransactionType{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_match_match_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OrderIDTransactionType) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderIDTransactionType) ProtoMessage() {}
+
+func (x *OrderIDTransactionType) ProtoReflect() protoreflect.Message {
+	mi := &file_match_match_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderIDTransactionType.ProtoReflect.Descriptor instead.
+func (*OrderIDTransactionType) Descriptor() ([]byte, []int) {
+	return file_match_match_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OrderIDTransactionType) GetOrderID() uint64 {
+	if x != nil {
+		return x.OrderID
+	}
+	return 0
+}
+
+func (x *OrderIDTransactionType) GetTransactionType() TransactionType {
+	if x != nil {
+		return x.TransactionType
+	}
+	return TransactionType_TransactionType_None
+}
+
+type GetMatchRecordReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to MatchRecord:
+	//	*GetMatchRecordReq_Id
+	//	*GetMatchRecordReq_Order
+	MatchRecord isGetMatchRecordReq_MatchRecord `protobuf_oneof:"matchRecord"`
+}
+
+func (x *GetMatchRecordReq) Reset() {
+	*x = GetMatchRecordReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_match_match_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetMatchRecordReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMatchRecordReq) ProtoMessage() {}
+
+func (x *GetMatchRecordReq) ProtoReflect() protoreflect.Message {
+	mi := &file_match_match_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMatchRecordReq.ProtoReflect.Descriptor instead.
+func (*GetMatchRecordReq) Descriptor() ([]byte, []int) {
+	return file_match_match_proto_rawDescGZIP(), []int{2}
+}
+
+func (m *GetMatchRecordReq) GetMatchRecord() isGetMatchRecordReq_MatchRecord {
+	if m != nil {
+		return m.MatchRecord
+	}
+	return nil
+}
+
+func (x *GetMatchRecordReq) GetId() uint64 {
+	if x, ok := x.GetMatchRecord().(*GetMatchRecordReq_Id); ok {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GetMatchRecordReq) GetOrder() *OrderIDTransactionType {
+	if x, ok := x.GetMatchRecord().(*GetMatchRecordReq_Order); ok {
+		return x.Order
+	}
+	return nil
+}
+
+type isGetMatchRecordReq_MatchRecord interface {
+	isGetMatchRecordReq_MatchRecord()
+}
+
+type GetMatchRecordReq_Id struct {
+	Id uint64 `protobuf:"varint,1,opt,name=id,proto3,oneof"`
+}
+
+type GetMatchRecordReq_Order struct {
+	Order *OrderIDTransactionType `protobuf:"bytes,2,opt,name=order,proto3,oneof"`
+}
+
+func (*GetMatchRecordReq_Id) isGetMatchRecordReq_MatchRecord() {}
+
+func (*GetMatchRecordReq_Order) isGetMatchRecordReq_MatchRecord() {}
+
+type GetMatchRecordRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MatchRecord *MatchRecord `protobuf:"bytes,1,opt,name=matchRecord,proto3" json:"matchRecord,omitempty"`
+}
+
+func (x *GetMatchRecordRes) Reset() {
+	*x = GetMatchRecordRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_match_match_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetMatchRecordRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMatchRecordRes) ProtoMessage() {}
+
+func (x *GetMatchRecordRes) ProtoReflect() protoreflect.Message {
+	mi := &file_match_match_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMatchRecordRes.ProtoReflect.Descriptor instead.
+func (*GetMatchRecordRes) Descriptor() ([]byte, []int) {
+	return file_match_match_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetMatchRecordRes) GetMatchRecord() *MatchRecord {
+	if x != nil {
+		return x.MatchRecord
+	}
+	return nil
+}
+
+type GetMatchRecordsReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id              []uint64            `protobuf:"varint,1,rep,packed,name=id,proto3" json:"id,omitempty"`
+	MemberID        []uint64            `protobuf:"varint,2,rep,packed,name=memberID,proto3" json:"memberID,omitempty"`
+	OrderID         []uint64            `protobuf:"varint,3,rep,packed,name=orderID,proto3" json:"orderID,omitempty"`
+	PositionID      []uint64            `protobuf:"varint,4,rep,packed,name=positionID,proto3" json:"positionID,omitempty"`
+	ExchangeCode    *string             `protobuf:"bytes,5,opt,name=exchangeCode,proto3,oneof" json:"exchangeCode,omitempty"`
+	ProductCode     *string             `protobuf:"bytes,6,opt,name=productCode,proto3,oneof" json:"productCode,omitempty"`
+	MatchStatus     []MatchStatus       `protobuf:"varint,7,rep,packed,name=matchStatus,proto3,enum=match.MatchStatus" json:"matchStatus,omitempty"`
+	TransactionType *TransactionType    `protobuf:"varint,8,opt,name=transactionType,proto3,enum=match.TransactionType,oneof" json:"transactionType,omitempty"`
+	LegType         *LegType            `protobuf:"varint,9,opt,name=legType,proto3,enum=match.LegType,oneof" json:"legType,omitempty"`
+	CreatedFrom     *int64              `protobuf:"varint,10,opt,name=createdFrom,proto3,oneof" json:"createdFrom,omitempty"`
+	CreatedTo       *int64              `protobuf:"varint,11,opt,name=createdTo,proto3,oneof" json:"createdTo,omitempty"`
+	Pagination      *general.Pagination `protobuf:"bytes,12,opt,name=pagination,proto3" json:"pagination,omitempty"`
+}
+
+func (x *GetMatchRecordsReq) Reset() {
+	*x = GetMatchRecordsReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_match_match_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetMatchRecordsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMatchRecordsReq) ProtoMessage() {}
+
+func (x *GetMatchRecordsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_match_match_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMatchRecordsReq.ProtoReflect.Descriptor instead.
+func (*GetMatchRecordsReq) Descriptor() ([]byte, []int) {
+	return file_match_match_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetMatchRecordsReq) GetId() []uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *GetMatchRecordsReq) GetMemberID() []uint64 {
+	if x != nil {
+		return x.MemberID
+	}
+	return nil
+}
+
+func (x *GetMatchRecordsReq) GetOrderID() []uint64 {
+	if x != nil {
+		return x.OrderID
+	}
+	return nil
+}
+
+func (x *GetMatchRecordsReq) GetPositionID() []uint64 {
+	if x != nil {
+		return x.PositionID
+	}
+	return nil
+}
+
+func (x *GetMatchRecordsReq) GetExchangeCode() string {
+	if x != nil && x.ExchangeCode != nil {
+		return *x.ExchangeCode
+	}
+	return ""
+}
+
+func (x *GetMatchRecordsReq) GetProductCode() string {
+	if x != nil && x.ProductCode != nil {
+		return *x.ProductCode
+	}
+	return ""
+}
+
+func (x *GetMatchRecordsReq) GetMatchStatus() []MatchStatus {
+	if x != nil {
+		return x.MatchStatus
+	}
+	return nil
+}
+
+func (x *GetMatchRecordsReq) GetTransactionType() TransactionType {
+	if x != nil && x.TransactionType != nil {
+		return *x.TransactionType
+	}
+	return TransactionType_TransactionType_None
+}
+
+func (x *GetMatchRecordsReq) GetLegType() LegType {
+	if x != nil && x.LegType != nil {
+		return *x.LegType
+	}
+	return LegType_LegType_None
+}
+
+func (x *GetMatchRecordsReq) GetCreatedFrom() int64 {
+	if x != nil && x.CreatedFrom != nil {
+		return *x.CreatedFrom
+	}
+	return 0
+}
+
+func (x *GetMatchRecordsReq) GetCreatedTo() int64 {
+	if x != nil && x.CreatedTo != nil {
+		return *x.CreatedTo
+	}
+	return 0
+}
+
+func (x *GetMatchRecordsReq) GetPagination() *general.Pagination {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+type GetMatchRecordsRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MatchRecords   []*MatchRecord          `protobuf:"bytes,1,rep,name=matchRecords,proto3" json:"matchRecords,omitempty"`
+	PaginationInfo *general.PaginationInfo `protobuf:"bytes,2,opt,name=paginationInfo,proto3" json:"paginationInfo,omitempty"`
+}
+
+func (x *GetMatchRecordsRes) Reset() {
+	*x = GetMatchRecordsRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_match_match_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetMatchRecordsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMatchRecordsRes) ProtoMessage() {}
+
+func (x *GetMatchRecordsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_match_match_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMatchRecordsRes.ProtoReflect.Descriptor instead.
+func (*GetMatchRecordsRes) Descriptor() ([]byte, []int) {
+	return file_match_match_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetMatchRecordsRes) GetMatchRecords() []*MatchRecord {
+	if x != nil {
+		return x.MatchRecords
+	}
+	return nil
+}
+
+func (x *GetMatchRecordsRes) GetPaginationInfo() *general.PaginationInfo {
+	if x != nil {
+		return x.PaginationInfo
+	}
+	return nil
+}
+
+var File_match_match_proto protoreflect.FileDescriptor
+
+var file_match_match_proto_rawDesc = []byte{
+	0x0a, 0x11, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x05, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x1a, 0x15, 0x67, 0x65, 0x6e, 0x65,
+	0x72, 0x61, 0x6c, 0x2f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0xf5, 0x0b, 0x0a, 0x0b, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x07, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x6d,
+	0x65, 0x6d, 0x62, 0x65, 0x72, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x6d,
+	0x65, 0x6d, 0x62, 0x65, 0x72, 0x49, 0x44, 0x12, 0x23, 0x0a, 0x0a, 0x70, 0x6f, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x49, 0x44, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x0a, 0x70,
+	0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x88, 0x01, 0x01, 0x12, 0x34, 0x0a, 0x0b,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x0b, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x40, 0x0a, 0x0f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x54, 0x79, 0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x6d, 0x61,
+	0x74, 0x63, 0x68, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54,
+	0x79, 0x70, 0x65, 0x52, 0x0f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x43, 0x6f, 0x64, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x78, 0x63, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x64,
+	0x75, 0x63, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70,
+	0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x2e, 0x0a, 0x09, 0x74, 0x72,
+	0x61, 0x64, 0x65, 0x54, 0x79, 0x70, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x54, 0x72, 0x61, 0x64, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x09, 0x74, 0x72, 0x61, 0x64, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2e, 0x0a, 0x09, 0x6f, 0x72,
+	0x64, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x09, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x23, 0x0a, 0x0a, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01,
+	0x52, 0x0a, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x88, 0x01, 0x01, 0x12,
+	0x28, 0x0a, 0x07, 0x6c, 0x65, 0x67, 0x54, 0x79, 0x70, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x0e, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4c, 0x65, 0x67, 0x54, 0x79, 0x70, 0x65,
+	0x52, 0x07, 0x6c, 0x65, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x23, 0x0a, 0x0a, 0x6c, 0x65, 0x67,
+	0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x04, 0x48, 0x02, 0x52,
+	0x0a, 0x6c, 0x65, 0x67, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x88, 0x01, 0x01, 0x12, 0x34,
+	0x0a, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x49, 0x6e, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x18, 0x0e, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x49, 0x6e, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x52, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x49, 0x6e, 0x46,
+	0x6f, 0x72, 0x63, 0x65, 0x12, 0x1f, 0x0a, 0x08, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x41, 0x74,
+	0x18, 0x0f, 0x20, 0x01, 0x28, 0x03, 0x48, 0x03, 0x52, 0x08, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65,
+	0x41, 0x74, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x09, 0x6f, 0x70, 0x65, 0x6e, 0x50, 0x72, 0x69,
+	0x63, 0x65, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x48, 0x04, 0x52, 0x09, 0x6f, 0x70, 0x65, 0x6e,
+	0x50, 0x72, 0x69, 0x63, 0x65, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0a, 0x63, 0x6c, 0x6f, 0x73,
+	0x65, 0x50, 0x72, 0x69, 0x63, 0x65, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x48, 0x05, 0x52, 0x0a,
+	0x63, 0x6c, 0x6f, 0x73, 0x65, 0x50, 0x72, 0x69, 0x63, 0x65, 0x88, 0x01, 0x01, 0x12, 0x16, 0x0a,
+	0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x12, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x0d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x18, 0x13, 0x20, 0x01, 0x28, 0x04, 0x48, 0x06, 0x52, 0x0d,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x88, 0x01, 0x01,
+	0x12, 0x10, 0x0a, 0x03, 0x66, 0x65, 0x65, 0x18, 0x14, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x66,
+	0x65, 0x65, 0x12, 0x2f, 0x0a, 0x10, 0x66, 0x65, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x18, 0x15, 0x20, 0x01, 0x28, 0x04, 0x48, 0x07, 0x52, 0x10,
+	0x66, 0x65, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44,
+	0x88, 0x01, 0x01, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x65, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x18,
+	0x16, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x65, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x12,
+	0x1b, 0x0a, 0x06, 0x6d, 0x61, 0x72, 0x67, 0x69, 0x6e, 0x18, 0x17, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x08, 0x52, 0x06, 0x6d, 0x61, 0x72, 0x67, 0x69, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x1c, 0x0a, 0x09,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x18, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x75, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x19, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x75,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x3a, 0x0a, 0x0d, 0x65, 0x78, 0x70, 0x69,
+	0x72, 0x65, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x14, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x4f, 0x75,
+	0x74, 0x63, 0x6f, 0x6d, 0x65, 0x52, 0x0d, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x4f, 0x75, 0x74,
+	0x63, 0x6f, 0x6d, 0x65, 0x12, 0x37, 0x0a, 0x0c, 0x70, 0x72, 0x69, 0x63, 0x69, 0x6e, 0x67, 0x4d,
+	0x6f, 0x64, 0x65, 0x6c, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x6d, 0x61, 0x74,
+	0x63, 0x68, 0x2e, 0x50, 0x72, 0x69, 0x63, 0x69, 0x6e, 0x67, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x52,
+	0x0c, 0x70, 0x72, 0x69, 0x63, 0x69, 0x6e, 0x67, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x1f, 0x0a,
+	0x08, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x41, 0x73, 0x6b, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x09, 0x52, 0x08, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x41, 0x73, 0x6b, 0x88, 0x01, 0x01, 0x12, 0x1f,
+	0x0a, 0x08, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x42, 0x69, 0x64, 0x18, 0x1d, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x0a, 0x52, 0x08, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x42, 0x69, 0x64, 0x88, 0x01, 0x01, 0x12,
+	0x21, 0x0a, 0x09, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x1e, 0x20, 0x01,
+	0x28, 0x03, 0x48, 0x0b, 0x52, 0x09, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x88,
+	0x01, 0x01, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x51, 0x75, 0x6f, 0x74, 0x65,
+	0x18, 0x1f, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x51, 0x75, 0x6f,
+	0x74, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x74, 0x72, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x20, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x72, 0x65, 0x74, 0x72, 0x79, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x37, 0x0a, 0x0c, 0x72, 0x65, 0x74, 0x72, 0x79, 0x4f, 0x75, 0x74, 0x63, 0x6f,
+	0x6d, 0x65, 0x18, 0x21, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68,
+	0x2e, 0x52, 0x65, 0x74, 0x72, 0x79, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x52, 0x0c, 0x72,
+	0x65, 0x74, 0x72, 0x79, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x72,
+	0x65, 0x74, 0x72, 0x79, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x22, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x72, 0x65, 0x74, 0x72, 0x79, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x2b, 0x0a, 0x08, 0x73,
+	0x61, 0x67, 0x61, 0x53, 0x74, 0x65, 0x70, 0x18, 0x23, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x53, 0x61, 0x67, 0x61, 0x53, 0x74, 0x65, 0x70, 0x52, 0x08,
+	0x73, 0x61, 0x67, 0x61, 0x53, 0x74, 0x65, 0x70, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x70, 0x6f, 0x73,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x6c, 0x65, 0x67, 0x4f, 0x72,
+	0x64, 0x65, 0x72, 0x49, 0x44, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65,
+	0x41, 0x74, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6f, 0x70, 0x65, 0x6e, 0x50, 0x72, 0x69, 0x63, 0x65,
+	0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x50, 0x72, 0x69, 0x63, 0x65, 0x42,
+	0x10, 0x0a, 0x0e, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49,
+	0x44, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x66, 0x65, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x6d, 0x61, 0x72, 0x67, 0x69,
+	0x6e, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x41, 0x73, 0x6b, 0x42, 0x0b,
+	0x0a, 0x09, 0x5f, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x42, 0x69, 0x64, 0x42, 0x0c, 0x0a, 0x0a, 0x5f,
+	0x71, 0x75, 0x6f, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x74, 0x0a, 0x16, 0x4f, 0x72, 0x64,
+	0x65, 0x72, 0x49, 0x44, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12, 0x40, 0x0a,
+	0x0f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0f,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x22,
+	0x6b, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x52, 0x65, 0x71, 0x12, 0x10, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x48, 0x00, 0x52, 0x02, 0x69, 0x64, 0x12, 0x35, 0x0a, 0x05, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4f, 0x72,
+	0x64, 0x65, 0x72, 0x49, 0x44, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x05, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x42, 0x0d, 0x0a,
+	0x0b, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x22, 0x49, 0x0a, 0x11,
+	0x47, 0x65, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x65,
+	0x73, 0x12, 0x34, 0x0a, 0x0b, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4d,
+	0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x0b, 0x6d, 0x61, 0x74, 0x63,
+	0x68, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x22, 0xd4, 0x04, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x4d,
+	0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x52, 0x65, 0x71, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1a,
+	0x0a, 0x08, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x03, 0x28, 0x04,
+	0x52, 0x08, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x72,
+	0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x03, 0x20, 0x03, 0x28, 0x04, 0x52, 0x07, 0x6f, 0x72, 0x64,
+	0x65, 0x72, 0x49, 0x44, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x49, 0x44, 0x18, 0x04, 0x20, 0x03, 0x28, 0x04, 0x52, 0x0a, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x49, 0x44, 0x12, 0x27, 0x0a, 0x0c, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x43, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0c, 0x65, 0x78,
+	0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x88, 0x01, 0x01, 0x12, 0x25, 0x0a,
+	0x0b, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x01, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x43, 0x6f, 0x64,
+	0x65, 0x88, 0x01, 0x01, 0x12, 0x34, 0x0a, 0x0b, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x74, 0x63,
+	0x68, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x0b, 0x6d,
+	0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x45, 0x0a, 0x0f, 0x74, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x48, 0x02, 0x52, 0x0f, 0x74,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x88, 0x01,
+	0x01, 0x12, 0x2d, 0x0a, 0x07, 0x6c, 0x65, 0x67, 0x54, 0x79, 0x70, 0x65, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4c, 0x65, 0x67, 0x54, 0x79,
+	0x70, 0x65, 0x48, 0x03, 0x52, 0x07, 0x6c, 0x65, 0x67, 0x54, 0x79, 0x70, 0x65, 0x88, 0x01, 0x01,
+	0x12, 0x25, 0x0a, 0x0b, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x03, 0x48, 0x04, 0x52, 0x0b, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
+	0x46, 0x72, 0x6f, 0x6d, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x54, 0x6f, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x48, 0x05, 0x52, 0x09, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x54, 0x6f, 0x88, 0x01, 0x01, 0x12, 0x33, 0x0a, 0x0a, 0x70, 0x61,
+	0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x6c, 0x2e, 0x50, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42,
+	0x0f, 0x0a, 0x0d, 0x5f, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x43, 0x6f, 0x64, 0x65,
+	0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x43, 0x6f, 0x64, 0x65,
+	0x42, 0x12, 0x0a, 0x10, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x54, 0x79, 0x70, 0x65, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x6c, 0x65, 0x67, 0x54, 0x79, 0x70, 0x65,
+	0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d,
+	0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x54, 0x6f, 0x22, 0x8d,
+	0x01, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x73, 0x52, 0x65, 0x73, 0x12, 0x36, 0x0a, 0x0c, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x61,
+	0x74, 0x63, 0x68, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52,
+	0x0c, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x3f, 0x0a,
+	0x0e, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x6c, 0x2e,
+	0x50, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0e,
+	0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x2a, 0xa5,
+	0x01, 0x0a, 0x0b, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x14,
+	0x0a, 0x10, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x4e, 0x6f,
+	0x6e, 0x65, 0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x5f, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x10, 0x01, 0x12, 0x16, 0x0a,
+	0x12, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x46, 0x61, 0x69,
+	0x6c, 0x65, 0x64, 0x10, 0x02, 0x12, 0x18, 0x0a, 0x14, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x5f, 0x46, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x10, 0x03, 0x12,
+	0x19, 0x0a, 0x15, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x43,
+	0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x10, 0x04, 0x12, 0x1a, 0x0a, 0x16, 0x4d, 0x61,
+	0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61,
+	0x63, 0x6b, 0x65, 0x64, 0x10, 0x05, 0x2a, 0x91, 0x01, 0x0a, 0x0f, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x14, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x4e, 0x6f,
+	0x6e, 0x65, 0x10, 0x00, 0x12, 0x20, 0x0a, 0x1c, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x4f, 0x70, 0x65, 0x6e, 0x50, 0x6f, 0x73, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x10, 0x01, 0x12, 0x21, 0x0a, 0x1d, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x50,
+	0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0x02, 0x12, 0x1f, 0x0a, 0x1b, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x4c, 0x69, 0x71,
+	0x75, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0x03, 0x2a, 0x46, 0x0a, 0x09, 0x54, 0x72,
+	0x61, 0x64, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x0e, 0x54, 0x72, 0x61, 0x64, 0x65,
+	0x54, 0x79, 0x70, 0x65, 0x5f, 0x4e, 0x6f, 0x6e, 0x65, 0x10, 0x00, 0x12, 0x11, 0x0a, 0x0d, 0x54,
+	0x72, 0x61, 0x64, 0x65, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x42, 0x75, 0x79, 0x10, 0x01, 0x12, 0x12,
+	0x0a, 0x0e, 0x54, 0x72, 0x61, 0x64, 0x65, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x53, 0x65, 0x6c, 0x6c,
+	0x10, 0x02, 0x2a, 0x4a, 0x0a, 0x09, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x12, 0x0a, 0x0e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x4e, 0x6f, 0x6e,
+	0x65, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65,
+	0x5f, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x4f, 0x72, 0x64,
+	0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x10, 0x02, 0x2a, 0x49,
+	0x0a, 0x07, 0x4c, 0x65, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x10, 0x0a, 0x0c, 0x4c, 0x65, 0x67,
+	0x54, 0x79, 0x70, 0x65, 0x5f, 0x4e, 0x6f, 0x6e, 0x65, 0x10, 0x00, 0x12, 0x16, 0x0a, 0x12, 0x4c,
+	0x65, 0x67, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x54, 0x61, 0x6b, 0x65, 0x50, 0x72, 0x6f, 0x66, 0x69,
+	0x74, 0x10, 0x01, 0x12, 0x14, 0x0a, 0x10, 0x4c, 0x65, 0x67, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x53,
+	0x74, 0x6f, 0x70, 0x4c, 0x6f, 0x73, 0x73, 0x10, 0x02, 0x2a, 0x8c, 0x01, 0x0a, 0x0b, 0x54, 0x69,
+	0x6d, 0x65, 0x49, 0x6e, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x12, 0x14, 0x0a, 0x10, 0x54, 0x69, 0x6d,
+	0x65, 0x49, 0x6e, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x5f, 0x4e, 0x6f, 0x6e, 0x65, 0x10, 0x00, 0x12,
+	0x13, 0x0a, 0x0f, 0x54, 0x69, 0x6d, 0x65, 0x49, 0x6e, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x5f, 0x47,
+	0x54, 0x43, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x54, 0x69, 0x6d, 0x65, 0x49, 0x6e, 0x46, 0x6f,
+	0x72, 0x63, 0x65, 0x5f, 0x49, 0x4f, 0x43, 0x10, 0x02, 0x12, 0x13, 0x0a, 0x0f, 0x54, 0x69, 0x6d,
+	0x65, 0x49, 0x6e, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x5f, 0x46, 0x4f, 0x4b, 0x10, 0x03, 0x12, 0x13,
+	0x0a, 0x0f, 0x54, 0x69, 0x6d, 0x65, 0x49, 0x6e, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x5f, 0x44, 0x41,
+	0x59, 0x10, 0x04, 0x12, 0x13, 0x0a, 0x0f, 0x54, 0x69, 0x6d, 0x65, 0x49, 0x6e, 0x46, 0x6f, 0x72,
+	0x63, 0x65, 0x5f, 0x47, 0x54, 0x44, 0x10, 0x05, 0x2a, 0x76, 0x0a, 0x0d, 0x45, 0x78, 0x70, 0x69,
+	0x72, 0x65, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x12, 0x45, 0x78, 0x70,
+	0x69, 0x72, 0x65, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x5f, 0x4e, 0x6f, 0x6e, 0x65, 0x10,
+	0x00, 0x12, 0x18, 0x0a, 0x14, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x4f, 0x75, 0x74, 0x63, 0x6f,
+	0x6d, 0x65, 0x5f, 0x46, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x10, 0x01, 0x12, 0x18, 0x0a, 0x14, 0x45,
+	0x78, 0x70, 0x69, 0x72, 0x65, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x5f, 0x4b, 0x69, 0x6c,
+	0x6c, 0x65, 0x64, 0x10, 0x02, 0x12, 0x19, 0x0a, 0x15, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x4f,
+	0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x5f, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x64, 0x10, 0x03,
+	0x2a, 0x8d, 0x01, 0x0a, 0x0c, 0x50, 0x72, 0x69, 0x63, 0x69, 0x6e, 0x67, 0x4d, 0x6f, 0x64, 0x65,
+	0x6c, 0x12, 0x15, 0x0a, 0x11, 0x50, 0x72, 0x69, 0x63, 0x69, 0x6e, 0x67, 0x4d, 0x6f, 0x64, 0x65,
+	0x6c, 0x5f, 0x4e, 0x6f, 0x6e, 0x65, 0x10, 0x00, 0x12, 0x16, 0x0a, 0x12, 0x50, 0x72, 0x69, 0x63,
+	0x69, 0x6e, 0x67, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x5f, 0x54, 0x6f, 0x75, 0x63, 0x68, 0x10, 0x01,
+	0x12, 0x14, 0x0a, 0x10, 0x50, 0x72, 0x69, 0x63, 0x69, 0x6e, 0x67, 0x4d, 0x6f, 0x64, 0x65, 0x6c,
+	0x5f, 0x4d, 0x69, 0x64, 0x10, 0x02, 0x12, 0x19, 0x0a, 0x15, 0x50, 0x72, 0x69, 0x63, 0x69, 0x6e,
+	0x67, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x5f, 0x46, 0x69, 0x78, 0x65, 0x64, 0x42, 0x70, 0x73, 0x10,
+	0x03, 0x12, 0x1d, 0x0a, 0x19, 0x50, 0x72, 0x69, 0x63, 0x69, 0x6e, 0x67, 0x4d, 0x6f, 0x64, 0x65,
+	0x6c, 0x5f, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x49, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x10, 0x04,
+	0x2a, 0xbb, 0x01, 0x0a, 0x0c, 0x52, 0x65, 0x74, 0x72, 0x79, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d,
+	0x65, 0x12, 0x15, 0x0a, 0x11, 0x52, 0x65, 0x74, 0x72, 0x79, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d,
+	0x65, 0x5f, 0x4e, 0x6f, 0x6e, 0x65, 0x10, 0x00, 0x12, 0x1a, 0x0a, 0x16, 0x52, 0x65, 0x74, 0x72,
+	0x79, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x5f, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64,
+	0x65, 0x64, 0x10, 0x01, 0x12, 0x1a, 0x0a, 0x16, 0x52, 0x65, 0x74, 0x72, 0x79, 0x4f, 0x75, 0x74,
+	0x63, 0x6f, 0x6d, 0x65, 0x5f, 0x45, 0x78, 0x68, 0x61, 0x75, 0x73, 0x74, 0x65, 0x64, 0x10, 0x02,
+	0x12, 0x21, 0x0a, 0x1d, 0x52, 0x65, 0x74, 0x72, 0x79, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65,
+	0x5f, 0x44, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x45, 0x78, 0x63, 0x65, 0x65, 0x64, 0x65,
+	0x64, 0x10, 0x03, 0x12, 0x1d, 0x0a, 0x19, 0x52, 0x65, 0x74, 0x72, 0x79, 0x4f, 0x75, 0x74, 0x63,
+	0x6f, 0x6d, 0x65, 0x5f, 0x4e, 0x6f, 0x6e, 0x52, 0x65, 0x74, 0x72, 0x79, 0x61, 0x62, 0x6c, 0x65,
+	0x10, 0x04, 0x12, 0x1a, 0x0a, 0x16, 0x52, 0x65, 0x74, 0x72, 0x79, 0x4f, 0x75, 0x74, 0x63, 0x6f,
+	0x6d, 0x65, 0x5f, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x10, 0x05, 0x2a, 0x93,
+	0x01, 0x0a, 0x08, 0x53, 0x61, 0x67, 0x61, 0x53, 0x74, 0x65, 0x70, 0x12, 0x11, 0x0a, 0x0d, 0x53,
+	0x61, 0x67, 0x61, 0x53, 0x74, 0x65, 0x70, 0x5f, 0x4e, 0x6f, 0x6e, 0x65, 0x10, 0x00, 0x12, 0x14,
+	0x0a, 0x10, 0x53, 0x61, 0x67, 0x61, 0x53, 0x74, 0x65, 0x70, 0x5f, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x10, 0x01, 0x12, 0x17, 0x0a, 0x13, 0x53, 0x61, 0x67, 0x61, 0x53, 0x74, 0x65, 0x70,
+	0x5f, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x65, 0x64, 0x10, 0x02, 0x12, 0x14, 0x0a,
+	0x10, 0x53, 0x61, 0x67, 0x61, 0x53, 0x74, 0x65, 0x70, 0x5f, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65,
+	0x64, 0x10, 0x03, 0x12, 0x15, 0x0a, 0x11, 0x53, 0x61, 0x67, 0x61, 0x53, 0x74, 0x65, 0x70, 0x5f,
+	0x46, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x10, 0x04, 0x12, 0x18, 0x0a, 0x14, 0x53, 0x61,
+	0x67, 0x61, 0x53, 0x74, 0x65, 0x70, 0x5f, 0x43, 0x6f, 0x6d, 0x70, 0x65, 0x6e, 0x73, 0x61, 0x74,
+	0x65, 0x64, 0x10, 0x05, 0x32, 0x9d, 0x01, 0x0a, 0x0c, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x44, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x74, 0x63,
+	0x68, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x18, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e,
+	0x47, 0x65, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x65,
+	0x71, 0x1a, 0x18, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x74,
+	0x63, 0x68, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x12, 0x47, 0x0a, 0x0f, 0x47,
+	0x65, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x19,
+	0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x19, 0x2e, 0x6d, 0x61, 0x74, 0x63,
+	0x68, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x73, 0x52, 0x65, 0x73, 0x42, 0x35, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x70, 0x61, 0x70, 0x65, 0x72, 0x2d, 0x74, 0x72, 0x61, 0x64, 0x65, 0x2d, 0x63,
+	0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x2f, 0x62, 0x65, 0x2d, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_match_match_proto_rawDescOnce sync.Once
+	file_match_match_proto_rawDescData = file_match_match_proto_rawDesc
+)
+
+func file_match_match_proto_rawDescGZIP() []byte {
+	file_match_match_proto_rawDescOnce.Do(func() {
+		file_match_match_proto_rawDescData = protoimpl.X.CompressGZIP(file_match_match_proto_rawDescData)
+	})
+	return file_match_match_proto_rawDescData
+}
+
+var file_match_match_proto_enumTypes = make([]protoimpl.EnumInfo, 10)
+var file_match_match_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_match_match_proto_goTypes = []interface{}{
+	(MatchStatus)(0),               // 0: match.MatchStatus
+	(TransactionType)(0),           // 1: match.TransactionType
+	(TradeType)(0),                 // 2: match.TradeType
+	(OrderType)(0),                 // 3: match.OrderType
+	(LegType)(0),                   // 4: match.LegType
+	(TimeInForce)(0),               // 5: match.TimeInForce
+	(ExpireOutcome)(0),             // 6: match.ExpireOutcome
+	(PricingModel)(0),              // 7: match.PricingModel
+	(RetryOutcome)(0),              // 8: match.RetryOutcome
+	(SagaStep)(0),                  // 9: match.SagaStep
+	(*MatchRecord)(nil),            // 10: match.MatchRecord
+	(*OrderIDTransactionType)(nil), // 11: match.OrderIDTransactionType
+	(*GetMatchRecordReq)(nil),      // 12: match.GetMatchRecordReq
+	(*GetMatchRecordRes)(nil),      // 13: match.GetMatchRecordRes
+	(*GetMatchRecordsReq)(nil),     // 14: match.GetMatchRecordsReq
+	(*GetMatchRecordsRes)(nil),     // 15: match.GetMatchRecordsRes
+	(*general.Pagination)(nil),     // 16: general.Pagination
+	(*general.PaginationInfo)(nil), // 17: general.PaginationInfo
+}
+var file_match_match_proto_depIdxs = []int32{
+	0,  // 0: match.MatchRecord.matchStatus:type_name -> match.MatchStatus
+	1,  // 1: match.MatchRecord.transactionType:type_name -> match.TransactionType
+	2,  // 2: match.MatchRecord.tradeType:type_name -> match.TradeType
+	3,  // 3: match.MatchRecord.orderType:type_name -> match.OrderType
+	4,  // 4: match.MatchRecord.legType:type_name -> match.LegType
+	5,  // 5: match.MatchRecord.timeInForce:type_name -> match.TimeInForce
+	6,  // 6: match.MatchRecord.expireOutcome:type_name -> match.ExpireOutcome
+	7,  // 7: match.MatchRecord.pricingModel:type_name -> match.PricingModel
+	8,  // 8: match.MatchRecord.retryOutcome:type_name -> match.RetryOutcome
+	9,  // 9: match.MatchRecord.sagaStep:type_name -> match.SagaStep
+	1,  // 10: match.OrderIDTransactionType.transactionType:type_name -> match.TransactionType
+	11, // 11: match.GetMatchRecordReq.order:type_name -> match.OrderIDTransactionType
+	10, // 12: match.GetMatchRecordRes.matchRecord:type_name -> match.MatchRecord
+	0,  // 13: match.GetMatchRecordsReq.matchStatus:type_name -> match.MatchStatus
+	1,  // 14: match.GetMatchRecordsReq.transactionType:type_name -> match.TransactionType
+	4,  // 15: match.GetMatchRecordsReq.legType:type_name -> match.LegType
+	16, // 16: match.GetMatchRecordsReq.pagination:type_name -> general.Pagination
+	10, // 17: match.GetMatchRecordsRes.matchRecords:type_name -> match.MatchRecord
+	17, // 18: match.GetMatchRecordsRes.paginationInfo:type_name -> general.PaginationInfo
+	12, // 19: match.MatchService.GetMatchRecord:input_type -> match.GetMatchRecordReq
+	14, // 20: match.MatchService.GetMatchRecords:input_type -> match.GetMatchRecordsReq
+	13, // 21: match.MatchService.GetMatchRecord:output_type -> match.GetMatchRecordRes
+	15, // 22: match.MatchService.GetMatchRecords:output_type -> match.GetMatchRecordsRes
+	21, // [21:23] is the sub-list for method output_type
+	19, // [19:21] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
+}
+
+func init() { file_match_match_proto_init() }
+func file_match_match_proto_init() {
+	if File_match_match_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_match_match_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MatchRecord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_match_match_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OrderIDTransactionType); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_match_match_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetMatchRecordReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_match_match_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetMatchRecordRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_match_match_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetMatchRecordsReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_match_match_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetMatchRecordsRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_match_match_proto_msgTypes[0].OneofWrappers = []interface{}{}
+	file_match_match_proto_msgTypes[2].OneofWrappers = []interface{}{
+		(*GetMatchRecordReq_Id)(nil),
+		(*GetMatchRecordReq_Order)(nil),
+	}
+	file_match_match_proto_msgTypes[4].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_match_match_proto_rawDesc,
+			NumEnums:      10,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_match_match_proto_goTypes,
+		DependencyIndexes: file_match_match_proto_depIdxs,
+		EnumInfos:         file_match_match_proto_enumTypes,
+		MessageInfos:      file_match_match_proto_msgTypes,
+	}.Build()
+	File_match_match_proto = out.File
+	file_match_match_proto_rawDesc = nil
+	file_match_match_proto_goTypes = nil
+	file_match_match_proto_depIdxs = nil
+}
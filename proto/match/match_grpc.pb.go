@@ -0,0 +1,139 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: match/match.proto
+
+package match
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// MatchServiceClient is the client API for MatchService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MatchServiceClient interface {
+	GetMatchRecord(ctx context.Context, in *GetMatchRecordReq, opts ...grpc.CallOption) (*GetMatchRecordRes, error)
+	GetMatchRecords(ctx context.Context, in *GetMatchRecordsReq, opts ...grpc.CallOption) (*GetMatchRecordsRes, error)
+}
+
+type matchServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMatchServiceClient(cc grpc.ClientConnInterface) MatchServiceClient {
+	return &matchServiceClient{cc}
+}
+
+func (c *matchServiceClient) GetMatchRecord(ctx context.Context, in *GetMatchRecordReq, opts ...grpc.CallOption) (*GetMatchRecordRes, error) {
+	out := new(GetMatchRecordRes)
+	err := c.cc.Invoke(ctx, "/match.MatchService/GetMatchRecord", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *matchServiceClient) GetMatchRecords(ctx context.Context, in *GetMatchRecordsReq, opts ...grpc.CallOption) (*GetMatchRecordsRes, error) {
+	out := new(GetMatchRecordsRes)
+	err := c.cc.Invoke(ctx, "/match.MatchService/GetMatchRecords", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MatchServiceServer is the server API for MatchService service.
+// All implementations should embed UnimplementedMatchServiceServer
+// for forward compatibility
+type MatchServiceServer interface {
+	GetMatchRecord(context.Context, *GetMatchRecordReq) (*GetMatchRecordRes, error)
+	GetMatchRecords(context.Context, *GetMatchRecordsReq) (*GetMatchRecordsRes, error)
+}
+
+// UnimplementedMatchServiceServer should be embedded to have forward compatible implementations.
+type UnimplementedMatchServiceServer struct {
+}
+
+func (UnimplementedMatchServiceServer) GetMatchRecord(context.Context, *GetMatchRecordReq) (*GetMatchRecordRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMatchRecord not implemented")
+}
+func (UnimplementedMatchServiceServer) GetMatchRecords(context.Context, *GetMatchRecordsReq) (*GetMatchRecordsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMatchRecords not implemented")
+}
+
+// UnsafeMatchServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MatchServiceServer will
+// result in compilation errors.
+type UnsafeMatchServiceServer interface {
+	mustEmbedUnimplementedMatchServiceServer()
+}
+
+func RegisterMatchServiceServer(s grpc.ServiceRegistrar, srv MatchServiceServer) {
+	s.RegisterService(&MatchService_ServiceDesc, srv)
+}
+
+func _MatchService_GetMatchRecord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMatchRecordReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatchServiceServer).GetMatchRecord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/match.MatchService/GetMatchRecord",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatchServiceServer).GetMatchRecord(ctx, req.(*GetMatchRecordReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MatchService_GetMatchRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMatchRecordsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatchServiceServer).GetMatchRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/match.MatchService/GetMatchRecords",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatchServiceServer).GetMatchRecords(ctx, req.(*GetMatchRecordsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MatchService_ServiceDesc is the grpc.ServiceDesc for MatchService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MatchService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "match.MatchService",
+	HandlerType: (*MatchServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetMatchRecord",
+			Handler:    _MatchService_GetMatchRecord_Handler,
+		},
+		{
+			MethodName: "GetMatchRecords",
+			Handler:    _MatchService_GetMatchRecords_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "match/match.proto",
+}
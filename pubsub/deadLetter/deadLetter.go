@@ -0,0 +1,174 @@
+package deadLetter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-match/lib/matchError"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/streadway/amqp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	Dataset_OpenPosition  = "openPosition"
+	Dataset_ClosePosition = "closePosition"
+)
+
+// 死信訊息的body為原始訊息, 失敗原因放在header
+const (
+	Header_OrderID         = "x-order-id"
+	Header_TransactionType = "x-transaction-type"
+	Header_FailCode        = "x-fail-code"
+	Header_FailReason      = "x-fail-reason"
+	Header_FailedAt        = "x-failed-at"
+	Header_ReplayCount     = "x-replay-count"
+)
+
+// ignoredCodes 訂單依條件取消, 並非撮合失敗, 不需重新撮合
+var ignoredCodes = map[codes.Code]bool{
+	status.Code(matchError.ErrOrderNotFilledImmediately): true,
+	status.Code(matchError.ErrOrderExpired):              true,
+	status.Code(matchError.ErrMarketClosed):              true,
+}
+
+var (
+	connection *amqp.Connection
+	channel    *amqp.Channel
+	exchange   string
+	lock       sync.Mutex
+)
+
+var (
+	ErrNotInitialized = errors.New("dead letter exchange not initialized")
+	ErrNotReplayable  = errors.New("match record is not replayable")
+)
+
+// Initialize 宣告死信exchange及各dataset的死信queue
+func Initialize(ctx context.Context, username, password, host, virtualHost, serviceName string) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	url := "amqp://" + username + ":" + password + "@" + host
+	if virtualHost != "" {
+		url += "/" + virtualHost
+	}
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return err
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	exchange = serviceName + ".deadLetter.direct"
+	if err := ch.ExchangeDeclare(exchange, amqp.ExchangeDirect, true, false, false, false, nil); err != nil {
+		conn.Close()
+		return err
+	}
+	for _, dataset := range []string{Dataset_OpenPosition, Dataset_ClosePosition} {
+		if _, err := ch.QueueDeclare(QueueName(dataset), true, false, false, false, nil); err != nil {
+			conn.Close()
+			return err
+		}
+		if err := ch.QueueBind(QueueName(dataset), dataset, exchange, false, nil); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	connection = conn
+	channel = ch
+	logging.Info(ctx, "[DeadLetter] exchange [%s] initialized.", exchange)
+	return nil
+}
+
+func Finalize(ctx context.Context) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if connection == nil {
+		return
+	}
+	channel.Close()
+	if err := connection.Close(); err != nil {
+		logging.Error(ctx, "[DeadLetter] failed to close connection: %v", err)
+	}
+	connection = nil
+	channel = nil
+}
+
+// Probe 連線中斷時回傳錯誤, 中斷後需重啟服務
+func Probe() error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if connection == nil || connection.IsClosed() {
+		return ErrNotInitialized
+	}
+	return nil
+}
+
+// QueueName 與訂閱的queue同樣依exported.domain.dataset.version命名
+func QueueName(dataset string) string {
+	return "private.order." + dataset + ".0.deadLetter"
+}
+
+// Publish 撮合失敗時將原始訊息連同失敗原因送到死信exchange
+// 送出失敗時將訊息記在log, 避免訊息遺失
+func Publish(ctx context.Context, dataset string, orderID uint64, transactionType dbModels.TransactionType, message interface{}, reason error) {
+	if reason == nil || ignoredCodes[status.Code(reason)] {
+		return
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		logging.Error(ctx, "[DeadLetter] failed to marshal message of order [%d]: %v", orderID, err)
+		return
+	}
+
+	replayCount := 0
+	if state, ok := ctx.Value(replayKey{}).(*replayState); ok {
+		replayCount = state.replayCount + 1
+		state.deadLettered = true
+	}
+
+	s, _ := status.FromError(reason)
+	publishing := amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now(),
+		Headers: amqp.Table{
+			Header_OrderID:         int64(orderID),
+			Header_TransactionType: int32(transactionType),
+			Header_FailCode:        int32(s.Code()),
+			Header_FailReason:      s.Message(),
+			Header_FailedAt:        time.Now().Unix(),
+			Header_ReplayCount:     int32(replayCount),
+		},
+		Body: body,
+	}
+
+	if err := publish(dataset, publishing); err != nil {
+		logging.Error(ctx, "[DeadLetter] failed to publish order [%d] reason [%v]: %v, message: %s", orderID, reason, err, string(body))
+		return
+	}
+	logging.Warn(ctx, "[DeadLetter] order [%d] dead-lettered to [%s]: %v", orderID, QueueName(dataset), reason)
+}
+
+func publish(dataset string, publishing amqp.Publishing) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if channel == nil {
+		return ErrNotInitialized
+	}
+	return channel.Publish(exchange, dataset, false, false, publishing)
+}
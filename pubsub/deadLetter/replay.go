@@ -0,0 +1,152 @@
+package deadLetter
+
+import (
+	"context"
+	"time"
+
+	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/streadway/amqp"
+)
+
+type replayKey struct{}
+
+type replayState struct {
+	replayCount  int
+	deadLettered bool
+}
+
+// Letter 死信訊息
+type Letter struct {
+	OrderID         uint64
+	TransactionType dbModels.TransactionType
+	FailCode        int
+	FailReason      string
+	FailedAt        time.Time
+	ReplayCount     int
+	Body            []byte
+}
+
+// ReplayResult 重新撮合的結果
+type ReplayResult struct {
+	Replayed []*Letter // 重新撮合成功
+	Failed   []*Letter // 再次失敗, 已重新送到死信
+	Kept     []*Letter // 未選取或撮合中斷, 留在死信queue
+}
+
+// IsReplay 是否為重新撮合死信
+func IsReplay(ctx context.Context) bool {
+	_, ok := ctx.Value(replayKey{}).(*replayState)
+	return ok
+}
+
+// Replayable 只有失敗且沒有未回滾扣款的撮合紀錄可以重新撮合
+func Replayable(matchRecord *dbModels.MatchRecordModel) bool {
+	switch matchRecord.MatchStatus {
+	case dbModels.MatchStatus_Rollbacked:
+		return true
+	case dbModels.MatchStatus_Failed, dbModels.MatchStatus_Cancelled:
+		return !matchRecord.TransactionID.Valid && !matchRecord.FeeTransactionID.Valid
+	default:
+		return false
+	}
+}
+
+// Replay 依序取出dataset的死信, 將selected選取的訊息交給handle重新撮合
+// limit為0時不限筆數, dryRun時只列出訊息不撮合
+// 撮合成功或再次失敗並重新送到死信時ack, 其餘訊息放回queue
+func Replay(ctx context.Context, dataset string, selected func(*Letter) bool, limit int, dryRun bool, handle func(context.Context, *Letter) error) (*ReplayResult, error) {
+	lock.Lock()
+	conn := connection
+	lock.Unlock()
+	if conn == nil {
+		return nil, ErrNotInitialized
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+	defer ch.Close()
+
+	// 只處理開始時queue中的訊息, 避免重新送回的死信被重複取出
+	queue, err := ch.QueueInspect(QueueName(dataset))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReplayResult{}
+	kept := []amqp.Delivery{}
+	defer func() {
+		for _, d := range kept {
+			if err := d.Nack(false, true); err != nil {
+				logging.Error(ctx, "[DeadLetter] failed to requeue delivery [%d]: %v", d.DeliveryTag, err)
+			}
+		}
+	}()
+
+	for i := 0; i < queue.Messages; i++ {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		d, ok, err := ch.Get(QueueName(dataset), false)
+		if err != nil {
+			return result, err
+		}
+		if !ok {
+			break
+		}
+		letter := parseLetter(d)
+
+		if dryRun || !selected(letter) || (limit > 0 && len(result.Replayed)+len(result.Failed) >= limit) {
+			kept = append(kept, d)
+			result.Kept = append(result.Kept, letter)
+			continue
+		}
+
+		state := &replayState{replayCount: letter.ReplayCount}
+		err = handle(context.WithValue(ctx, replayKey{}, state), letter)
+		if err != nil && !state.deadLettered {
+			logging.Error(ctx, "[DeadLetter] failed to replay order [%d]: %v", letter.OrderID, err)
+			kept = append(kept, d)
+			result.Kept = append(result.Kept, letter)
+			continue
+		}
+
+		if err := d.Ack(false); err != nil {
+			return result, err
+		}
+		if state.deadLettered {
+			result.Failed = append(result.Failed, letter)
+		} else {
+			result.Replayed = append(result.Replayed, letter)
+		}
+	}
+	return result, nil
+}
+
+func parseLetter(d amqp.Delivery) *Letter {
+	letter := &Letter{
+		Body: d.Body,
+	}
+	if v, ok := d.Headers[Header_OrderID].(int64); ok {
+		letter.OrderID = uint64(v)
+	}
+	if v, ok := d.Headers[Header_TransactionType].(int32); ok {
+		letter.TransactionType = dbModels.TransactionType(v)
+	}
+	if v, ok := d.Headers[Header_FailCode].(int32); ok {
+		letter.FailCode = int(v)
+	}
+	if v, ok := d.Headers[Header_FailReason].(string); ok {
+		letter.FailReason = v
+	}
+	if v, ok := d.Headers[Header_FailedAt].(int64); ok {
+		letter.FailedAt = time.Unix(v, 0)
+	}
+	if v, ok := d.Headers[Header_ReplayCount].(int32); ok {
+		letter.ReplayCount = int(v)
+	}
+	return letter
+}
@@ -0,0 +1,151 @@
+package matchEvent
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/paper-trade-chatbot/be-match/dao/matchEventOutboxDao"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/paper-trade-chatbot/be-match/models/mqModels"
+	bePubsub "github.com/paper-trade-chatbot/be-pubsub"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+var ErrPublisherNotReady = errors.New("match event publisher not ready")
+
+// Publishers 由pubsub.Initialize以GetPublisher帶入, 避免撮合的package與pubsub互相import
+type Publishers struct {
+	Started    func(context.Context) bePubsub.TPublisher[*mqModels.MatchStartedModel]
+	Filled     func(context.Context) bePubsub.TPublisher[*mqModels.MatchFilledModel]
+	Failed     func(context.Context) bePubsub.TPublisher[*mqModels.MatchFailedModel]
+	RolledBack func(context.Context) bePubsub.TPublisher[*mqModels.MatchRolledBackModel]
+}
+
+var publishers *Publishers
+
+func Initialize(p *Publishers) {
+	publishers = p
+}
+
+// Started 寫入開始撮合事件, 需與建立撮合紀錄在同一個transaction
+func Started(tx *gorm.DB, matchRecord *dbModels.MatchRecordModel) error {
+	return save(tx, dbModels.MatchEventType_Started, newEvent(matchRecord, nil))
+}
+
+// Finished 依撮合紀錄的狀態寫入成交, 失敗或回滾事件, 需與更新撮合紀錄在同一個transaction
+// 撮合紀錄仍為待處理時由RecoverMatch接手, 不寫入事件
+func Finished(tx *gorm.DB, matchRecord *dbModels.MatchRecordModel, orderErr error) error {
+	eventType := dbModels.MatchEventType_None
+	switch matchRecord.MatchStatus {
+	case dbModels.MatchStatus_Finished:
+		eventType = dbModels.MatchEventType_Filled
+	case dbModels.MatchStatus_Failed, dbModels.MatchStatus_Cancelled:
+		eventType = dbModels.MatchEventType_Failed
+	case dbModels.MatchStatus_Rollbacked:
+		eventType = dbModels.MatchEventType_RolledBack
+	default:
+		return nil
+	}
+	return save(tx, eventType, newEvent(matchRecord, orderErr))
+}
+
+// Relay 將outbox的事件送到rabbitmq, 失敗時回傳錯誤由matchEventRelay重送
+func Relay(ctx context.Context, outbox *dbModels.MatchEventOutboxModel) error {
+	if publishers == nil {
+		return ErrPublisherNotReady
+	}
+
+	switch outbox.EventType {
+	case dbModels.MatchEventType_Started:
+		return produce(ctx, publishers.Started, outbox.Payload)
+	case dbModels.MatchEventType_Filled:
+		return produce(ctx, publishers.Filled, outbox.Payload)
+	case dbModels.MatchEventType_Failed:
+		return produce(ctx, publishers.Failed, outbox.Payload)
+	case dbModels.MatchEventType_RolledBack:
+		return produce(ctx, publishers.RolledBack, outbox.Payload)
+	}
+	return fmt.Errorf("unknown match event type %d", outbox.EventType)
+}
+
+func save(tx *gorm.DB, eventType dbModels.MatchEventType, event mqModels.MatchEventModel) error {
+	eventID, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	event.EventID = eventID.String()
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = matchEventOutboxDao.New(tx, &dbModels.MatchEventOutboxModel{
+		EventID:       event.EventID,
+		EventType:     eventType,
+		OrderID:       event.OrderID,
+		MatchRecordID: event.MatchRecordID,
+		Payload:       string(payload),
+		OutboxStatus:  dbModels.OutboxStatus_Pending,
+	})
+	return err
+}
+
+func newEvent(matchRecord *dbModels.MatchRecordModel, orderErr error) mqModels.MatchEventModel {
+	event := mqModels.MatchEventModel{
+		OrderID:          matchRecord.OrderID,
+		MatchRecordID:    matchRecord.ID,
+		MemberID:         matchRecord.MemberID,
+		TransactionType:  matchRecord.TransactionType,
+		ExchangeCode:     matchRecord.ExchangeCode,
+		ProductCode:      matchRecord.ProductCode,
+		TradeType:        matchRecord.TradeType,
+		PositionID:       nullID(matchRecord.PositionID),
+		Price:            matchRecord.OpenPrice,
+		Amount:           matchRecord.Amount,
+		Fee:              matchRecord.Fee,
+		TransactionID:    nullID(matchRecord.TransactionID),
+		FeeTransactionID: nullID(matchRecord.FeeTransactionID),
+		OccurredAt:       time.Now().Unix(),
+	}
+	if matchRecord.TransactionType != dbModels.TransactionType_OpenPosition {
+		event.Price = matchRecord.ClosePrice
+	}
+	if orderErr != nil {
+		failCode := uint64(status.Code(orderErr))
+		s, _ := status.FromError(orderErr)
+		failReason := s.Message()
+		event.FailCode = &failCode
+		event.FailReason = &failReason
+	}
+	return event
+}
+
+func nullID(id sql.NullInt64) *uint64 {
+	if !id.Valid {
+		return nil
+	}
+	v := uint64(id.Int64)
+	return &v
+}
+
+// produce 事件的各型別都內嵌MatchEventModel, 以outbox的payload還原後送出
+func produce[T interface{}](ctx context.Context, getPublisher func(context.Context) bePubsub.TPublisher[*T], payload string) error {
+	publisher := getPublisher(ctx)
+	if publisher == nil {
+		return ErrPublisherNotReady
+	}
+
+	model := new(T)
+	if err := json.Unmarshal([]byte(payload), model); err != nil {
+		return err
+	}
+	_, err := publisher.Produce(ctx, model)
+	return err
+}
@@ -3,43 +3,114 @@ package matchOpenPosition
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"time"
 
 	common "github.com/paper-trade-chatbot/be-common"
 	"github.com/paper-trade-chatbot/be-common/database"
 	"github.com/paper-trade-chatbot/be-common/logging"
 	"github.com/paper-trade-chatbot/be-match/dao/matchRecordDao"
+	"github.com/paper-trade-chatbot/be-match/dao/pendingOrderDao"
+	"github.com/paper-trade-chatbot/be-match/dao/positionMarginDao"
+	"github.com/paper-trade-chatbot/be-match/dao/positionTriggerDao"
+	"github.com/paper-trade-chatbot/be-match/lib/fee"
+	"github.com/paper-trade-chatbot/be-match/lib/limitOrder"
+	"github.com/paper-trade-chatbot/be-match/lib/margin"
+	"github.com/paper-trade-chatbot/be-match/lib/matchError"
+	"github.com/paper-trade-chatbot/be-match/lib/matchLock"
+	"github.com/paper-trade-chatbot/be-match/lib/pricing"
+	"github.com/paper-trade-chatbot/be-match/lib/retry"
+	"github.com/paper-trade-chatbot/be-match/lib/timeInForce"
+	"github.com/paper-trade-chatbot/be-match/lib/tradingCalendar"
+	"github.com/paper-trade-chatbot/be-match/lib/trailingStop"
 	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/paper-trade-chatbot/be-match/models/mqModels"
+	"github.com/paper-trade-chatbot/be-match/pubsub/deadLetter"
+	"github.com/paper-trade-chatbot/be-match/pubsub/matchEvent"
 	"github.com/paper-trade-chatbot/be-match/service"
 	"github.com/paper-trade-chatbot/be-proto/order"
 	"github.com/paper-trade-chatbot/be-proto/product"
 	"github.com/paper-trade-chatbot/be-proto/quote"
 	"github.com/paper-trade-chatbot/be-proto/wallet"
-	"github.com/paper-trade-chatbot/be-pubsub/order/openPosition/rabbitmq"
 	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
 )
 
-func MatchOpenPosition(ctx context.Context, model *rabbitmq.OpenPositionModel) error {
+func MatchOpenPosition(ctx context.Context, model *mqModels.OpenPositionModel) error {
 	logging.Info(ctx, "[MatchOpenPosition] model: %#v", model)
 	db := database.GetDB()
 	deal := false
-	retryCount := 0
+	var retrier *retry.Retrier
 	var transactionID uint64 = 0
+	var feeTransactionID uint64 = 0
 	unitPrice := decimal.Decimal{}
+	initialMargin := decimal.Decimal{}
 	var orderErr error
 	orderProcess := order.OrderProcess_OrderProcess_Failed
 	var expire *int64
+	parked := false
+	duplicated := false
+	recorded := false
 
-	if _, err := service.Impl.OrderIntf.UpdateOrderProcess(ctx, &order.UpdateOrderProcessReq{
-		Id:           model.ID,
-		OrderProcess: order.OrderProcess_OrderProcess_Matching,
-	}); err != nil {
-		logging.Error(ctx, "[MatchOpenPosition] failed to Update OrderProcess [%d]: %v", model.ID, err)
+	pendingOrder, err := pendingOrderDao.Get(db, &pendingOrderDao.QueryModel{
+		OrderID:       model.ID,
+		PendingStatus: dbModels.PendingStatus_Pending,
+	})
+	if err != nil {
+		logging.Error(ctx, "[MatchOpenPosition] failed to get pendingOrder [%d]: %v", model.ID, err)
+		return err
+	}
+
+	// 訊息重送時已有撮合紀錄, 除了掛單重新撮合之外不再重複撮合及扣款
+	existing, err := matchRecordDao.GetByOrder(db, model.ID, dbModels.TransactionType_OpenPosition)
+	if err != nil {
+		logging.Error(ctx, "[MatchOpenPosition] failed to get matchRecord of order [%d]: %v", model.ID, err)
+		return err
+	}
+	// 重新撮合死信時沿用失敗的撮合紀錄
+	replaying := existing != nil && pendingOrder == nil && deadLetter.IsReplay(ctx)
+	if replaying && !deadLetter.Replayable(existing) {
+		logging.Warn(ctx, "[MatchOpenPosition] order [%d] matchRecord [%d] status [%d] is not replayable.", model.ID, existing.ID, existing.MatchStatus)
+		return deadLetter.ErrNotReplayable
+	}
+	if existing != nil && pendingOrder == nil && !replaying {
+		logging.Warn(ctx, "[MatchOpenPosition] order [%d] redelivered, matchRecord [%d] status [%d]. skipped.", model.ID, existing.ID, existing.MatchStatus)
+		return nil
 	}
 
 	defer func() {
+		if duplicated {
+			return
+		}
 		if orderErr != nil {
+			if pendingOrder != nil && pendingOrder.PendingStatus == dbModels.PendingStatus_Processing {
+				// 撮合紀錄結算前就失敗, 掛單一併失敗
+				pendingStatus := dbModels.PendingStatus_Failed
+				if err := pendingOrderDao.Modify(db, pendingOrder, &pendingOrderDao.UpdateModel{
+					PendingStatus: &pendingStatus,
+				}); err != nil {
+					logging.Error(ctx, "[MatchOpenPosition] failed to Modify pendingOrder [%d]: %v", pendingOrder.ID, err)
+				}
+			}
+			if !recorded {
+				// 撮合紀錄建立前就失敗
+				if err := matchEvent.Finished(db, &dbModels.MatchRecordModel{
+					OrderID:         model.ID,
+					MemberID:        model.MemberID,
+					MatchStatus:     dbModels.MatchStatus_Failed,
+					TransactionType: dbModels.TransactionType_OpenPosition,
+					ExchangeCode:    model.ExchangeCode,
+					ProductCode:     model.ProductCode,
+					TradeType:       dbModels.TradeType(model.TradeType),
+					Amount:          model.Amount,
+				}, orderErr); err != nil {
+					logging.Error(ctx, "[MatchOpenPosition] failed to save match event of order [%d]: %v", model.ID, err)
+				}
+			}
+			deadLetter.Publish(ctx, deadLetter.Dataset_OpenPosition, model.ID, dbModels.TransactionType_OpenPosition, model, orderErr)
 			failCode := uint64(status.Code(orderErr))
 			s, _ := status.FromError(orderErr)
 			remark := s.Message()
@@ -68,39 +139,348 @@ func MatchOpenPosition(ctx context.Context, model *rabbitmq.OpenPositionModel) e
 		}
 	}()
 
-	matchRecord := &dbModels.MatchRecordModel{
-		OrderID:         model.ID,
-		MemberID:        model.MemberID,
-		MatchStatus:     dbModels.MatchStatus_Pending,
-		TransactionType: dbModels.TransactionType_OpenPosition,
-		ExchangeCode:    model.ExchangeCode,
-		ProductCode:     model.ProductCode,
-		TradeType:       dbModels.TradeType(model.TradeType),
-		Amount:          model.Amount,
+	// 同一會員的撮合依訂單順序進行, 避免同時讀到相同餘額而重複扣款
+	locks, err := matchLock.AcquireAll(ctx, model.ID, matchLock.MemberKey(model.MemberID))
+	if err != nil {
+		logging.Error(ctx, "[MatchOpenPosition] failed to lock order [%d]: %v", model.ID, err)
+		if pendingOrder != nil {
+			// 尚未取得掛單, 留待workjob下次重新撮合
+			duplicated = true
+			return err
+		}
+		orderErr = err
+		return err
 	}
+	defer locks.Release()
 
-	if _, err := matchRecordDao.New(db, matchRecord); err != nil {
-		logging.Error(ctx, "[MatchOpenPosition] failed to new matchRecord: %v", err)
+	// 等待鎖期間訂單可能已被其他實例撮合或到期取消, 取得鎖後重新確認
+	current, err := matchRecordDao.GetByOrder(db, model.ID, dbModels.TransactionType_OpenPosition)
+	if err != nil {
+		logging.Error(ctx, "[MatchOpenPosition] failed to get matchRecord of order [%d]: %v", model.ID, err)
 		orderErr = err
 		return err
 	}
+	if current != nil && (existing == nil || current.MatchStatus != existing.MatchStatus) {
+		logging.Warn(ctx, "[MatchOpenPosition] order [%d] matchRecord [%d] changed to status [%d] while waiting for lock. skipped.", model.ID, current.ID, current.MatchStatus)
+		duplicated = true
+		return nil
+	}
+	existing = current
+
+	// 掛單先改為撮合中, 同時只有一方能撮合或到期取消
+	if pendingOrder != nil {
+		pendingStatus := dbModels.PendingStatus_Processing
+		claimed, err := pendingOrderDao.ModifyIfPending(db, pendingOrder, &pendingOrderDao.UpdateModel{
+			PendingStatus: &pendingStatus,
+		})
+		if err != nil {
+			logging.Error(ctx, "[MatchOpenPosition] failed to claim pendingOrder [%d]: %v", pendingOrder.ID, err)
+			orderErr = err
+			return err
+		}
+		if !claimed {
+			logging.Warn(ctx, "[MatchOpenPosition] pendingOrder [%d] of order [%d] is no longer pending. skipped.", pendingOrder.ID, model.ID)
+			duplicated = true
+			return nil
+		}
+		pendingOrder.PendingStatus = pendingStatus
+	}
+
+	if _, err := service.Impl.OrderIntf.UpdateOrderProcess(ctx, &order.UpdateOrderProcessReq{
+		Id:           model.ID,
+		OrderProcess: order.OrderProcess_OrderProcess_Matching,
+	}); err != nil {
+		logging.Error(ctx, "[MatchOpenPosition] failed to Update OrderProcess [%d]: %v", model.ID, err)
+	}
+
+	orderType := model.OrderType
+	if orderType == dbModels.OrderType_None {
+		orderType = dbModels.OrderType_Market
+	}
+	if orderType == dbModels.OrderType_Limit && !model.LimitPrice.Valid {
+		logging.Error(ctx, "[MatchOpenPosition] limit order [%d] without limit price: %v", model.ID, common.ErrInvalidParam)
+		orderErr = common.ErrInvalidParam
+		return common.ErrInvalidParam
+	}
+
+	tif := model.TimeInForce
+	if tif == dbModels.TimeInForce_None {
+		tif = dbModels.TimeInForce_GTC
+	}
+	if !timeInForce.IsSupported(tif) {
+		logging.Error(ctx, "[MatchOpenPosition] order [%d] time in force [%d]: %v", model.ID, tif, matchError.ErrUnsupportedTimeInForce)
+		orderErr = matchError.ErrUnsupportedTimeInForce
+		return matchError.ErrUnsupportedTimeInForce
+	}
+	expireOutcome := dbModels.ExpireOutcome_None
+	// 掛單沿用掛單時的到期時間, 到期時在撮合紀錄載入後才取消
+	var expireAt sql.NullTime
+	var expireErr error
+	if pendingOrder != nil {
+		expireAt = pendingOrder.ExpireAt
+		if expireAt.Valid && !expireAt.Time.After(time.Now()) {
+			expireErr = matchError.ErrOrderExpired
+		}
+	} else {
+		expireAt, expireErr = timeInForce.ExpireAt(ctx, tif, model.ExchangeCode, model.ExpireAt)
+	}
+
+	var matchRecord *dbModels.MatchRecordModel
+	if pendingOrder != nil {
+		// 掛單重新撮合時沿用原本的撮合紀錄
+		matchRecord, err = matchRecordDao.Get(db, &matchRecordDao.QueryModel{
+			ID: pendingOrder.MatchRecordID,
+		})
+		if err != nil {
+			logging.Error(ctx, "[MatchOpenPosition] failed to get matchRecord [%d]: %v", pendingOrder.MatchRecordID, err)
+			orderErr = err
+			return err
+		}
+	} else {
+		matchRecord = &dbModels.MatchRecordModel{
+			OrderID:         model.ID,
+			MemberID:        model.MemberID,
+			MatchStatus:     dbModels.MatchStatus_Pending,
+			SagaStep:        dbModels.SagaStep_Created,
+			TransactionType: dbModels.TransactionType_OpenPosition,
+			ExchangeCode:    model.ExchangeCode,
+			ProductCode:     model.ProductCode,
+			TradeType:       dbModels.TradeType(model.TradeType),
+			OrderType:       orderType,
+			LimitPrice:      model.LimitPrice,
+			TimeInForce:     tif,
+			ExpireAt:        expireAt,
+			Amount:          model.Amount,
+		}
+
+		if replaying {
+			renewed := false
+			if err := db.Transaction(func(tx *gorm.DB) error {
+				var err error
+				if renewed, err = matchRecordDao.RenewIfFailed(tx, existing.ID, matchRecord); err != nil || !renewed {
+					return err
+				}
+				return matchEvent.Started(tx, matchRecord)
+			}); err != nil {
+				logging.Error(ctx, "[MatchOpenPosition] failed to renew matchRecord [%d]: %v", existing.ID, err)
+				orderErr = err
+				return err
+			}
+			if !renewed {
+				logging.Warn(ctx, "[MatchOpenPosition] order [%d] is being replayed by another process. skipped.", model.ID)
+				duplicated = true
+				return deadLetter.ErrNotReplayable
+			}
+		} else if err := db.Transaction(func(tx *gorm.DB) error {
+			if _, err := matchRecordDao.New(tx, matchRecord); err != nil {
+				return err
+			}
+			return matchEvent.Started(tx, matchRecord)
+		}); err != nil {
+			if existing, _ := matchRecordDao.GetByOrder(db, model.ID, dbModels.TransactionType_OpenPosition); existing != nil {
+				// 同時收到重送的訊息, 由先建立撮合紀錄的一方撮合
+				logging.Warn(ctx, "[MatchOpenPosition] order [%d] is being matched by matchRecord [%d]. skipped.", model.ID, existing.ID)
+				duplicated = true
+				return nil
+			}
+			logging.Error(ctx, "[MatchOpenPosition] failed to new matchRecord: %v", err)
+			orderErr = err
+			return err
+		}
+	}
+	// 撮合紀錄建立後, 撮合事件與撮合紀錄一起寫入
+	recorded = true
+
 	var openPrice *decimal.NullDecimal = nil
+	var pricingModel pricing.PricingModel
+	rawQuote := &pricing.Quote{}
 	var positionID *sql.NullInt64 = nil
 
 	defer func() {
-		if matchRecord.MatchStatus != dbModels.MatchStatus_Finished {
+		if parked {
+			return
+		}
+
+		if matchRecord.MatchStatus != dbModels.MatchStatus_Finished &&
+			matchRecord.MatchStatus != dbModels.MatchStatus_Cancelled &&
+			matchRecord.MatchStatus != dbModels.MatchStatus_Rollbacked &&
+			matchRecord.MatchStatus != dbModels.MatchStatus_Pending {
+			matchRecord.MatchStatus = dbModels.MatchStatus_Failed
+		}
+		// 撮合失敗時結束撮合紀錄, 中斷(沒有orderErr)時保持待處理由recoverMatch接手
+		if orderErr != nil && matchRecord.MatchStatus == dbModels.MatchStatus_Pending {
 			matchRecord.MatchStatus = dbModels.MatchStatus_Failed
 		}
 
-		if err := matchRecordDao.Modify(db, matchRecord, &matchRecordDao.UpdateModel{
-			MatchStatus: &matchRecord.MatchStatus,
-			PositionID:  positionID,
-			OpenPrice:   openPrice,
+		if retrier != nil {
+			matchRecord.RetryCount = retrier.Attempts()
+			matchRecord.RetryOutcome = retrier.Outcome()
+			matchRecord.RetryError = retrier.LastError()
+		}
+
+		if positionID != nil {
+			matchRecord.PositionID = *positionID
+		}
+		if openPrice != nil {
+			matchRecord.OpenPrice = *openPrice
+		}
+
+		// 撮合結果與撮合事件在同一個transaction寫入, 失敗時撮合紀錄維持待處理由recoverMatch接手
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			if err := matchRecordDao.Modify(tx, matchRecord, &matchRecordDao.UpdateModel{
+				MatchStatus:      &matchRecord.MatchStatus,
+				SagaStep:         &matchRecord.SagaStep,
+				RetryCount:       &matchRecord.RetryCount,
+				RetryOutcome:     &matchRecord.RetryOutcome,
+				RetryError:       &matchRecord.RetryError,
+				ExpireOutcome:    &expireOutcome,
+				PricingModel:     &matchRecord.PricingModel,
+				QuoteAsk:         &rawQuote.Ask,
+				QuoteBid:         &rawQuote.Bid,
+				QuoteTime:        &rawQuote.Time,
+				StaleQuote:       &matchRecord.StaleQuote,
+				Fee:              &matchRecord.Fee,
+				FeeTransactionID: &matchRecord.FeeTransactionID,
+				Leverage:         &matchRecord.Leverage,
+				Margin:           &matchRecord.Margin,
+				PositionID:       positionID,
+				OpenPrice:        openPrice,
+			}); err != nil {
+				return err
+			}
+
+			// 撮合中斷保持待處理時, 掛單隨recoverMatch接續或補償一起結束
+			if pendingOrder != nil && matchRecord.MatchStatus != dbModels.MatchStatus_Pending {
+				pendingStatus := dbModels.PendingStatus_Failed
+				if matchRecord.MatchStatus == dbModels.MatchStatus_Finished {
+					pendingStatus = dbModels.PendingStatus_Filled
+				} else if matchRecord.MatchStatus == dbModels.MatchStatus_Cancelled {
+					pendingStatus = dbModels.PendingStatus_Cancelled
+				}
+				if err := pendingOrderDao.Modify(tx, pendingOrder, &pendingOrderDao.UpdateModel{
+					PendingStatus: &pendingStatus,
+				}); err != nil {
+					return err
+				}
+				pendingOrder.PendingStatus = pendingStatus
+			}
+
+			return matchEvent.Finished(tx, matchRecord, orderErr)
 		}); err != nil {
 			logging.Error(ctx, "[MatchOpenPosition] failed to Modify matchRecord [%d]: %v", model.ID, err)
 		}
 	}()
 
+	if expireErr != nil {
+		logging.Error(ctx, "[MatchOpenPosition] failed to get expire time of order [%d]: %v", model.ID, expireErr)
+		if errors.Is(expireErr, matchError.ErrOrderExpired) {
+			matchRecord.MatchStatus = dbModels.MatchStatus_Cancelled
+			expireOutcome = dbModels.ExpireOutcome_Expired
+		}
+		orderErr = expireErr
+		return expireErr
+	}
+
+	// saveStep 每完成一個步驟就寫回撮合紀錄, 當機時由recoverMatch接續或補償
+	saveStep := func(sagaStep dbModels.SagaStep) {
+		matchRecord.SagaStep = sagaStep
+		if err := matchRecordDao.Modify(db, matchRecord, &matchRecordDao.UpdateModel{
+			SagaStep:         &matchRecord.SagaStep,
+			TransactionID:    &matchRecord.TransactionID,
+			Fee:              &matchRecord.Fee,
+			FeeTransactionID: &matchRecord.FeeTransactionID,
+			Margin:           &matchRecord.Margin,
+		}); err != nil {
+			logging.Error(ctx, "[MatchOpenPosition] failed to save saga step [%d] of order [%d]: %v", sagaStep, model.ID, err)
+		}
+	}
+
+	// park 掛單等待價格或開盤, 由workjob重新送進撮合
+	park := func() error {
+		if pendingOrder == nil && replaying {
+			// 重新撮合死信時重新開啟原本的掛單
+			closed, err := pendingOrderDao.Get(db, &pendingOrderDao.QueryModel{
+				OrderID: model.ID,
+			})
+			if err != nil {
+				logging.Error(ctx, "[MatchOpenPosition] failed to get pendingOrder [%d]: %v", model.ID, err)
+				orderErr = err
+				return err
+			}
+			if closed != nil {
+				pendingStatus := dbModels.PendingStatus_Pending
+				if err := pendingOrderDao.Modify(db, closed, &pendingOrderDao.UpdateModel{
+					PendingStatus: &pendingStatus,
+				}); err != nil {
+					logging.Error(ctx, "[MatchOpenPosition] failed to reopen pendingOrder [%d]: %v", closed.ID, err)
+					orderErr = err
+					return err
+				}
+				pendingOrder = closed
+			}
+		}
+		if pendingOrder != nil && pendingOrder.PendingStatus == dbModels.PendingStatus_Processing {
+			// 放回掛單中, 由workjob之後再送進撮合
+			pendingStatus := dbModels.PendingStatus_Pending
+			if err := pendingOrderDao.Modify(db, pendingOrder, &pendingOrderDao.UpdateModel{
+				PendingStatus: &pendingStatus,
+			}); err != nil {
+				logging.Error(ctx, "[MatchOpenPosition] failed to release pendingOrder [%d]: %v", pendingOrder.ID, err)
+				orderErr = err
+				return err
+			}
+			pendingOrder.PendingStatus = pendingStatus
+		}
+		if pendingOrder == nil {
+			if _, err := pendingOrderDao.New(db, &dbModels.PendingOrderModel{
+				OrderID:         model.ID,
+				MatchRecordID:   matchRecord.ID,
+				MemberID:        model.MemberID,
+				PendingStatus:   dbModels.PendingStatus_Pending,
+				TransactionType: dbModels.TransactionType_OpenPosition,
+				ExchangeCode:    model.ExchangeCode,
+				ProductCode:     model.ProductCode,
+				TradeType:       dbModels.TradeType(model.TradeType),
+				OrderType:       orderType,
+				LimitPrice:      model.LimitPrice.Decimal,
+				TimeInForce:     tif,
+				ExpireAt:        expireAt,
+				Amount:          model.Amount,
+			}); err != nil {
+				logging.Error(ctx, "[MatchOpenPosition] failed to new pendingOrder: %v", err)
+				orderErr = err
+				return err
+			}
+		}
+		parked = true
+		orderProcess = order.OrderProcess_OrderProcess_Waiting
+		return nil
+	}
+
+	// pause 扣款前下游服務斷路時改為掛單, 等斷路器恢復後由workjob重新撮合, 不耗盡重試次數讓訂單失敗
+	// IOC不可掛單, 仍直接失敗
+	pause := func(err error) bool {
+		return status.Code(err) == codes.Unavailable && !service.Available() && !timeInForce.IsImmediate(tif)
+	}
+
+	// 休市時市價單及IOC直接拒絕, 其餘限價單掛單等開盤
+	marketOpen, err := tradingCalendar.IsOpen(ctx, model.ExchangeCode, time.Now())
+	if err != nil {
+		logging.Error(ctx, "[MatchOpenPosition] failed to check trading calendar [%s]: %v", model.ExchangeCode, err)
+		orderErr = err
+		return err
+	}
+	if !marketOpen {
+		if orderType == dbModels.OrderType_Market || timeInForce.IsImmediate(tif) {
+			logging.Info(ctx, "[MatchOpenPosition] order [%d] rejected, market [%s] closed.", model.ID, model.ExchangeCode)
+			matchRecord.MatchStatus = dbModels.MatchStatus_Cancelled
+			orderErr = matchError.ErrMarketClosed
+			return matchError.ErrMarketClosed
+		}
+		logging.Info(ctx, "[MatchOpenPosition] order [%d] pending, market [%s] closed.", model.ID, model.ExchangeCode)
+		return park()
+	}
+
 	productRes, err := service.Impl.ProductIntf.GetProduct(ctx, &product.GetProductReq{
 		Product: &product.GetProductReq_Code{
 			Code: &product.ExchangeCodeProductCode{
@@ -111,14 +491,40 @@ func MatchOpenPosition(ctx context.Context, model *rabbitmq.OpenPositionModel) e
 	})
 
 	if err != nil {
+		if pause(err) {
+			logging.Warn(ctx, "[MatchOpenPosition] circuit breaker open, order [%d] pending: %v", model.ID, err)
+			return park()
+		}
 		logging.Error(ctx, "[MatchOpenPosition] failed to get product [%s][%s]: %v", model.ExchangeCode, model.ProductCode, err)
 		orderErr = err
 		return err
 	}
 
-	for !deal && retryCount <= 10 {
+	pricingModel, err = pricing.GetPricingModel(db, model.ExchangeCode, model.ProductCode)
+	if err != nil {
+		logging.Error(ctx, "[MatchOpenPosition] failed to get pricing model [%s][%s]: %v", model.ExchangeCode, model.ProductCode, err)
+		orderErr = err
+		return err
+	}
+	matchRecord.PricingModel = pricingModel.Type()
 
-		retryCount++
+	maxQuoteAge, err := pricing.GetMaxQuoteAge(db, model.ExchangeCode, model.ProductCode)
+	if err != nil {
+		logging.Error(ctx, "[MatchOpenPosition] failed to get max quote age [%s][%s]: %v", model.ExchangeCode, model.ProductCode, err)
+		orderErr = err
+		return err
+	}
+	staleQuote := false
+
+	matchRecord.Leverage, err = margin.GetLeverage(db, dbModels.ProductType(productRes.Product.Type))
+	if err != nil {
+		logging.Error(ctx, "[MatchOpenPosition] failed to get leverage [%s][%s]: %v", model.ExchangeCode, model.ProductCode, err)
+		orderErr = err
+		return err
+	}
+
+	retrier = retry.New(ctx, timeInForce.RetryPolicy(tif))
+	for !deal && retrier.Next() {
 
 		walletRes, err := service.Impl.WalletIntf.GetWallets(ctx, &wallet.GetWalletsReq{
 			Wallet: &wallet.GetWalletsReq_MemberID{
@@ -130,6 +536,10 @@ func MatchOpenPosition(ctx context.Context, model *rabbitmq.OpenPositionModel) e
 			if err == nil {
 				err = common.ErrNoSuchWallet
 			}
+			if pause(err) {
+				logging.Warn(ctx, "[MatchOpenPosition] circuit breaker open, order [%d] pending: %v", model.ID, err)
+				return park()
+			}
 			logging.Error(ctx, "[MatchOpenPosition] failed to get wallet by member[%d] currency[%s]: %v", model.MemberID, productRes.Product.CurrencyCode, err)
 			orderErr = err
 			return err
@@ -142,63 +552,174 @@ func MatchOpenPosition(ctx context.Context, model *rabbitmq.OpenPositionModel) e
 			return err
 		}
 
-		flag := quote.GetQuotesReq_GetFlag_None
-		key := ""
-		if model.TradeType == rabbitmq.TradeType_Buy {
-			flag = quote.GetQuotesReq_GetFlag_Ask
-			key = "ask"
-		} else {
-			flag = quote.GetQuotesReq_GetFlag_Bid
-			key = "bid"
-		}
-
 		getFrom := "000000"
 		getTo := "000000"
 		quoteRes, err := service.Impl.QuoteIntf.GetQuotes(ctx, &quote.GetQuotesReq{
 			ProductIDs: []int64{productRes.Product.Id},
-			Flag:       flag,
+			Flag:       pricing.QuoteFlag,
 			GetFrom:    &getFrom,
 			GetTo:      &getTo,
 		})
 		if err != nil || len(quoteRes.Quotes) == 0 {
+			if err == nil {
+				err = matchError.ErrNoQuote
+			}
+			if !retrier.Retryable(err) {
+				logging.Error(ctx, "[MatchOpenPosition] GetQuotes failed: %v", err)
+				orderErr = err
+				return err
+			}
+			if pause(err) {
+				logging.Warn(ctx, "[MatchOpenPosition] circuit breaker open, order [%d] pending: %v", model.ID, err)
+				return park()
+			}
 			logging.Warn(ctx, "[MatchOpenPosition] GetQuotes failed. retry later: %v", err)
 			continue
 		}
 
-		unitPriceString, ok := quoteRes.Quotes[0].Quotes[key]
-		if !ok {
-			logging.Warn(ctx, "[MatchOpenPosition] GetQuotes no [%s] field. retry later", key)
+		rawQuote = pricing.NewQuote(quoteRes.Quotes[0].Quotes)
+		if maxQuoteAge > 0 && !rawQuote.Time.Valid {
+			logging.Warn(ctx, "[MatchOpenPosition] quote of [%s][%s] has no quote time. skip stale check.", model.ExchangeCode, model.ProductCode)
+		}
+		staleQuote = pricing.IsStale(rawQuote, maxQuoteAge, time.Now())
+		matchRecord.StaleQuote = staleQuote
+		if staleQuote {
+			logging.Warn(ctx, "[MatchOpenPosition] quote of [%s][%s] at %v is stale. retry later.", model.ExchangeCode, model.ProductCode, rawQuote.Time.Time)
+			retrier.Retryable(matchError.ErrStaleQuote)
 			continue
 		}
-
-		unitPrice, err = decimal.NewFromString(unitPriceString)
+		unitPrice, err = pricingModel.Price(rawQuote, dbModels.TradeType(model.TradeType), dbModels.TransactionType_OpenPosition, model.Amount)
 		if err != nil {
-			logging.Warn(ctx, "[MatchOpenPosition] GetQuotes NewFromString failed. retry later: %v", err)
+			if !retrier.Retryable(err) {
+				logging.Error(ctx, "[MatchOpenPosition] failed to price by model [%d]: %v", pricingModel.Type(), err)
+				orderErr = err
+				return err
+			}
+			logging.Warn(ctx, "[MatchOpenPosition] failed to price by model [%d]. retry later: %v", pricingModel.Type(), err)
 			continue
 		}
 
-		if balance.LessThan(unitPrice.Mul(model.Amount)) {
+		matchRecord.Fee, err = fee.GetFee(db, model.ExchangeCode, model.ProductCode, dbModels.TransactionType_OpenPosition, unitPrice.Mul(model.Amount))
+		if err != nil {
+			logging.Error(ctx, "[MatchOpenPosition] failed to get fee [%s][%s]: %v", model.ExchangeCode, model.ProductCode, err)
+			orderErr = err
+			return err
+		}
+
+		if orderType == dbModels.OrderType_Limit &&
+			!limitOrder.IsMarketable(dbModels.TradeType(model.TradeType), dbModels.TransactionType_OpenPosition, unitPrice, model.LimitPrice.Decimal) {
+			if timeInForce.IsImmediate(tif) {
+				logging.Info(ctx, "[MatchOpenPosition] limit order [%d] not marketable at %s, cancelled.", model.ID, unitPrice.String())
+				matchRecord.MatchStatus = dbModels.MatchStatus_Cancelled
+				expireOutcome = dbModels.ExpireOutcome_Killed
+				orderErr = matchError.ErrOrderNotFilledImmediately
+				return matchError.ErrOrderNotFilledImmediately
+			}
+			logging.Info(ctx, "[MatchOpenPosition] limit order [%d] not marketable at %s, pending.", model.ID, unitPrice.String())
+			return park()
+		}
+
+		// 開倉只扣保證金, 其餘由槓桿補足
+		initialMargin = margin.InitialMargin(unitPrice.Mul(model.Amount), matchRecord.Leverage)
+		if balance.LessThan(initialMargin.Add(matchRecord.Fee)) {
 			logging.Error(ctx, "[MatchOpenPosition] balance not enough: %v", common.ErrInsufficientBalance)
 			orderErr = common.ErrInsufficientBalance
+			return common.ErrInsufficientBalance
+		}
+
+		// 租約遺失時其他實例可能已取得鎖, 不再扣款
+		if err := locks.Check(); err != nil {
+			logging.Error(ctx, "[MatchOpenPosition] failed to debit order [%d]: %v", model.ID, err)
+			orderErr = err
 			return err
 		}
 
 		beforeAmount := balance.String()
+		// 備註帶訂單id, 對帳時可找回未寫入撮合紀錄的交易
+		transactionRemark := fmt.Sprintf("order %d", model.ID)
 		transactionRes, err := service.Impl.WalletIntf.Transaction(ctx, &wallet.TransactionReq{
 			WalletID:     walletRes.Wallets[0].Id,
 			Action:       wallet.Action_Action_OPEN,
-			Amount:       unitPrice.Mul(model.Amount).Neg().String(),
+			Amount:       initialMargin.Neg().String(),
 			Currency:     productRes.Product.CurrencyCode,
 			CommitterID:  model.MemberID,
 			BeforeAmount: &beforeAmount,
+			Remark:       &transactionRemark,
 		})
 		if err != nil {
+			if !retrier.Retryable(err) {
+				logging.Error(ctx, "[MatchOpenPosition] Transaction failed: %v", err)
+				orderErr = err
+				return err
+			}
+			if pause(err) {
+				logging.Warn(ctx, "[MatchOpenPosition] circuit breaker open, order [%d] pending: %v", model.ID, err)
+				return park()
+			}
 			logging.Warn(ctx, "[MatchOpenPosition] Transaction failed. retry later: %v", err)
 			continue
 		}
 
 		transactionID = transactionRes.Id
+		matchRecord.TransactionID = sql.NullInt64{Valid: true, Int64: int64(transactionID)}
+		matchRecord.Margin = decimal.NewNullDecimal(initialMargin)
+		saveStep(dbModels.SagaStep_Transacted)
+
+		// 手續費另外記一筆交易, 備註帶訂單id方便對帳
+		if matchRecord.Fee.IsPositive() {
+			remark := fmt.Sprintf("fee of order %d", model.ID)
+			feeRes, err := service.Impl.WalletIntf.Transaction(ctx, &wallet.TransactionReq{
+				WalletID:    walletRes.Wallets[0].Id,
+				Action:      wallet.Action_Action_OPEN,
+				Amount:      matchRecord.Fee.Neg().String(),
+				Currency:    productRes.Product.CurrencyCode,
+				CommitterID: model.MemberID,
+				Remark:      &remark,
+			})
+			if err != nil {
+				logging.Warn(ctx, "[MatchOpenPosition] fee Transaction failed. retry later: %v", err)
+				if _, err := service.Impl.WalletIntf.RollbackTransaction(ctx, &wallet.RollbackTransactionReq{
+					Id:           transactionID,
+					RollbackerID: model.MemberID,
+				}); err != nil {
+					// 回滾失敗時保持待處理, 由recoverMatch依已扣款步驟補償
+					logging.Error(ctx, "[MatchOpenPosition] failed to RollbackTransaction [%d]: %v", model.ID, err)
+					return err
+				}
+				transactionID = 0
+				matchRecord.TransactionID = sql.NullInt64{}
+				saveStep(dbModels.SagaStep_Created)
+				if !retrier.Retryable(err) {
+					orderErr = err
+					return err
+				}
+				if pause(err) {
+					logging.Warn(ctx, "[MatchOpenPosition] circuit breaker open, order [%d] pending: %v", model.ID, err)
+					return park()
+				}
+				continue
+			}
+			feeTransactionID = feeRes.Id
+			matchRecord.FeeTransactionID = sql.NullInt64{Valid: true, Int64: int64(feeTransactionID)}
+			saveStep(dbModels.SagaStep_Transacted)
+		}
+
 		deal = true
+		retrier.Succeed()
+	}
+
+	if !deal && staleQuote {
+		logging.Error(ctx, "[MatchOpenPosition] failed to match [%d]: %v", model.ID, matchError.ErrStaleQuote)
+		orderErr = matchError.ErrStaleQuote
+		return matchError.ErrStaleQuote
+	}
+
+	if !deal && timeInForce.IsImmediate(tif) {
+		logging.Error(ctx, "[MatchOpenPosition] failed to match [%d] immediately: %v", model.ID, matchError.ErrOrderNotFilledImmediately)
+		matchRecord.MatchStatus = dbModels.MatchStatus_Cancelled
+		expireOutcome = dbModels.ExpireOutcome_Killed
+		orderErr = matchError.ErrOrderNotFilledImmediately
+		return matchError.ErrOrderNotFilledImmediately
 	}
 
 	if !deal {
@@ -207,12 +728,19 @@ func MatchOpenPosition(ctx context.Context, model *rabbitmq.OpenPositionModel) e
 		return common.ErrExceedRetryTimes
 	}
 
+	// 已扣款但租約遺失, 保持待處理由recoverMatch取得鎖後接續或補償
+	if err := locks.Check(); err != nil {
+		logging.Error(ctx, "[MatchOpenPosition] failed to finish order [%d]: %v", model.ID, err)
+		return err
+	}
+
 	if _, err := service.Impl.OrderIntf.UpdateOrderProcess(ctx, &order.UpdateOrderProcessReq{
 		Id:           model.ID,
 		OrderProcess: order.OrderProcess_OrderProcess_Matched,
 	}); err != nil {
 		logging.Error(ctx, "[MatchOpenPosition] failed to Update OrderProcess [%d]: %v", model.ID, err)
 	}
+	saveStep(dbModels.SagaStep_Matched)
 
 	res, err := service.Impl.OrderIntf.FinishOpenPositionOrder(ctx, &order.FinishOpenPositionOrderReq{
 		Id:                  model.ID,
@@ -222,16 +750,46 @@ func MatchOpenPosition(ctx context.Context, model *rabbitmq.OpenPositionModel) e
 	})
 	if err != nil {
 		logging.Error(ctx, "[MatchOpenPosition] FinishOpenPositionOrder failed: %v", err)
+		compensated := true
 		if _, err := service.Impl.WalletIntf.RollbackTransaction(ctx, &wallet.RollbackTransactionReq{
 			Id:           transactionID,
 			RollbackerID: model.MemberID,
 		}); err != nil {
 			logging.Error(ctx, "[MatchOpenPosition] failed to RollbackTransaction [%d]: %v", model.ID, err)
+			compensated = false
+		} else {
+			matchRecord.TransactionID = sql.NullInt64{}
+		}
+		if feeTransactionID != 0 {
+			if _, err := service.Impl.WalletIntf.RollbackTransaction(ctx, &wallet.RollbackTransactionReq{
+				Id:           feeTransactionID,
+				RollbackerID: model.MemberID,
+			}); err != nil {
+				logging.Error(ctx, "[MatchOpenPosition] failed to RollbackTransaction of fee [%d]: %v", model.ID, err)
+				compensated = false
+			} else {
+				matchRecord.FeeTransactionID = sql.NullInt64{}
+			}
+		}
+		if !compensated {
+			// 回滾失敗時保持待處理, 只留下尚未回滾的交易id, 由recoverMatch再補償
+			saveStep(dbModels.SagaStep_Matched)
+			return err
 		}
+		matchRecord.TransactionID = sql.NullInt64{Valid: true, Int64: int64(transactionID)}
+		if feeTransactionID != 0 {
+			matchRecord.FeeTransactionID = sql.NullInt64{Valid: true, Int64: int64(feeTransactionID)}
+		}
+		matchRecord.MatchStatus = dbModels.MatchStatus_Rollbacked
+		matchRecord.SagaStep = dbModels.SagaStep_Compensated
 		return err
 	}
 
 	matchRecord.MatchStatus = dbModels.MatchStatus_Finished
+	matchRecord.SagaStep = dbModels.SagaStep_Finished
+	if tif != dbModels.TimeInForce_GTC {
+		expireOutcome = dbModels.ExpireOutcome_Filled
+	}
 	positionID = &sql.NullInt64{
 		Valid: true,
 		Int64: int64(res.PositionID),
@@ -241,8 +799,124 @@ func MatchOpenPosition(ctx context.Context, model *rabbitmq.OpenPositionModel) e
 		Decimal: unitPrice,
 	}
 
+	if _, err := positionMarginDao.New(db, &dbModels.PositionMarginModel{
+		PositionID:   res.PositionID,
+		MemberID:     model.MemberID,
+		ExchangeCode: model.ExchangeCode,
+		ProductCode:  model.ProductCode,
+		TradeType:    dbModels.TradeType(model.TradeType),
+		MarginStatus: dbModels.MarginStatus_Open,
+		Leverage:     matchRecord.Leverage,
+		OpenPrice:    unitPrice,
+		Amount:       model.Amount,
+		Margin:       initialMargin,
+	}); err != nil {
+		logging.Error(ctx, "[MatchOpenPosition] failed to new positionMargin [%d]: %v", res.PositionID, err)
+	}
+
+	if err := newPositionTriggers(db, model, res.PositionID, unitPrice); err != nil {
+		logging.Error(ctx, "[MatchOpenPosition] failed to new position triggers [%d]: %v", res.PositionID, err)
+	}
+
+	if model.Bracket != nil {
+		if err := newBracketLegs(db, model, res.PositionID, unitPrice); err != nil {
+			logging.Error(ctx, "[MatchOpenPosition] failed to new bracket legs [%d]: %v", res.PositionID, err)
+		}
+	}
+
 	orderProcess = order.OrderProcess_OrderProcess_Finished
 	expireTime := int64(time.Minute)
 	expire = &expireTime
 	return nil
 }
+
+// newPositionTriggers 將訂單帶的停損停利掛到成交後的倉位上
+func newPositionTriggers(db *gorm.DB, model *mqModels.OpenPositionModel, positionID uint64, unitPrice decimal.Decimal) error {
+	triggers := []*dbModels.PositionTriggerModel{}
+	newTrigger := func(triggerType dbModels.TriggerType, triggerPrice decimal.Decimal) *dbModels.PositionTriggerModel {
+		return &dbModels.PositionTriggerModel{
+			PositionID:    positionID,
+			MemberID:      model.MemberID,
+			ExchangeCode:  model.ExchangeCode,
+			ProductCode:   model.ProductCode,
+			TradeType:     dbModels.TradeType(model.TradeType),
+			TriggerType:   triggerType,
+			TriggerStatus: dbModels.TriggerStatus_Active,
+			TriggerPrice:  triggerPrice,
+		}
+	}
+
+	if model.StopLossPrice.Valid {
+		triggers = append(triggers, newTrigger(dbModels.TriggerType_StopLoss, model.StopLossPrice.Decimal))
+	}
+	if model.TakeProfitPrice.Valid {
+		triggers = append(triggers, newTrigger(dbModels.TriggerType_TakeProfit, model.TakeProfitPrice.Decimal))
+	}
+	if model.TrailMode != dbModels.TrailMode_None && model.TrailValue.Valid {
+		// 移動停損的水位從成交價開始
+		trigger := newTrigger(dbModels.TriggerType_TrailingStop,
+			trailingStop.StopPrice(model.TrailMode, model.TrailValue.Decimal, dbModels.TradeType(model.TradeType), unitPrice))
+		trigger.TrailMode = model.TrailMode
+		trigger.TrailValue = model.TrailValue
+		triggers = append(triggers, trigger)
+	}
+	if len(triggers) == 0 {
+		return nil
+	}
+
+	_, err := positionTriggerDao.News(db, triggers)
+	return err
+}
+
+// newBracketLegs 建立附加的停利及停損單, 每張附加單各有一筆撮合紀錄及一個觸發,
+// 觸發以母單id為二擇一群組. 停利觸價後以限價IOC平倉附加單數量, 停損觸價後以市價平倉
+func newBracketLegs(db *gorm.DB, model *mqModels.OpenPositionModel, positionID uint64, unitPrice decimal.Decimal) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		legs := []struct {
+			legType     dbModels.LegType
+			triggerType dbModels.TriggerType
+			orderType   dbModels.OrderType
+			price       decimal.Decimal
+		}{
+			{dbModels.LegType_TakeProfit, dbModels.TriggerType_TakeProfit, dbModels.OrderType_Limit, model.Bracket.TakeProfitPrice},
+			{dbModels.LegType_StopLoss, dbModels.TriggerType_StopLoss, dbModels.OrderType_Market, model.Bracket.StopLossPrice},
+		}
+
+		for _, leg := range legs {
+			legRecord := &dbModels.MatchRecordModel{
+				OrderID:         model.ID,
+				MemberID:        model.MemberID,
+				PositionID:      sql.NullInt64{Valid: true, Int64: int64(positionID)},
+				MatchStatus:     dbModels.MatchStatus_Pending,
+				TransactionType: dbModels.TransactionType_ClosePosition,
+				ExchangeCode:    model.ExchangeCode,
+				ProductCode:     model.ProductCode,
+				TradeType:       dbModels.TradeType(model.TradeType),
+				OrderType:       leg.orderType,
+				LimitPrice:      decimal.NewNullDecimal(leg.price),
+				LegType:         leg.legType,
+				OpenPrice:       decimal.NewNullDecimal(unitPrice),
+				Amount:          model.Amount,
+			}
+			if _, err := matchRecordDao.New(tx, legRecord); err != nil {
+				return err
+			}
+
+			if _, err := positionTriggerDao.News(tx, []*dbModels.PositionTriggerModel{{
+				PositionID:    positionID,
+				MemberID:      model.MemberID,
+				ExchangeCode:  model.ExchangeCode,
+				ProductCode:   model.ProductCode,
+				TradeType:     dbModels.TradeType(model.TradeType),
+				TriggerType:   leg.triggerType,
+				TriggerStatus: dbModels.TriggerStatus_Active,
+				TriggerPrice:  leg.price,
+				ParentOrderID: sql.NullInt64{Valid: true, Int64: int64(model.ID)},
+				MatchRecordID: sql.NullInt64{Valid: true, Int64: int64(legRecord.ID)},
+			}}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,56 @@
+package pubsub
+
+import (
+	"github.com/paper-trade-chatbot/be-pubsub/rabbitmq"
+	beJson "github.com/paper-trade-chatbot/be-pubsub/rabbitmq/json"
+	"github.com/streadway/amqp"
+)
+
+// newPublisher 建立be-match的事件publisher, 並宣告exchange及綁定queue
+// be-pubsub只宣告queue, exchange不存在時送出的訊息會使channel關閉
+func newPublisher[T interface{}](username, password, host, virualHost, dataset string) (*beJson.PublisherImpl[T], error) {
+	publisher, err := beJson.NewPublisher[T](
+		&rabbitmq.PublisherConfig{
+			ConfigImpl: rabbitmq.ConfigImpl{
+				Username:    username,
+				Password:    password,
+				Host:        host,
+				VirtualHost: virualHost,
+				Exported:    rabbitmq.RABBITMQ_EXPORTED_PRIVATE,
+				Domain:      []string{"match"},
+				Dataset:     dataset,
+				ContentType: rabbitmq.RABBITMQ_CONTENT_TYPE_JSON,
+			},
+			ExchangeName: "match",
+			ExchangeType: rabbitmq.RABBITMQ_EXCHANGE_TYPE_DIRECT,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := publisher.Channel.ExchangeDeclare(
+		publisher.GetExchangeName(), // name
+		amqp.ExchangeDirect,         // type
+		true,                        // durable
+		false,                       // auto-deleted
+		false,                       // internal
+		false,                       // no-wait
+		nil,                         // arguments
+	); err != nil {
+		publisher.Close()
+		return nil, err
+	}
+
+	if err := publisher.Channel.QueueBind(
+		publisher.GetRoutingKey(),   // queue
+		publisher.GetRoutingKey(),   // routing key
+		publisher.GetExchangeName(), // exchange
+		false,                       // no-wait
+		nil,                         // arguments
+	); err != nil {
+		publisher.Close()
+		return nil, err
+	}
+
+	return publisher, nil
+}
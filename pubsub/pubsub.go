@@ -7,17 +7,17 @@ import (
 
 	"github.com/paper-trade-chatbot/be-common/config"
 	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-match/models/mqModels"
+	"github.com/paper-trade-chatbot/be-match/pubsub/deadLetter"
 	"github.com/paper-trade-chatbot/be-match/pubsub/matchClosePosition"
+	"github.com/paper-trade-chatbot/be-match/pubsub/matchEvent"
 	"github.com/paper-trade-chatbot/be-match/pubsub/matchOpenPosition"
 	bePubsub "github.com/paper-trade-chatbot/be-pubsub"
-
-	rabbitmqClosePosition "github.com/paper-trade-chatbot/be-pubsub/order/closePosition/rabbitmq"
-	rabbitmqOpenPosition "github.com/paper-trade-chatbot/be-pubsub/order/openPosition/rabbitmq"
 )
 
 var publishers = map[string]interface{}{}
 var publisherLock sync.RWMutex
-var subscribers = []bePubsub.Pubsub{}
+var subscribers = []listener{}
 
 //Initialize
 // please register all instance creator of publisher here
@@ -33,23 +33,96 @@ func Initialize(ctx context.Context) {
 		publisherLock.Lock()
 		defer publisherLock.Unlock()
 
+		if pub, err := newPublisher[*mqModels.MatchStartedModel](
+			config.GetString("RABBITMQ_USERNAME"),
+			config.GetString("RABBITMQ_PASSWORD"),
+			config.GetString("RABBITMQ_HOST"),
+			config.GetString("RABBITMQ_VIRTUAL_HOST"),
+			"matchStarted",
+		); err != nil {
+			logging.Error(ctx, "NewPublisher error %v", err)
+			panic(err)
+		} else {
+			registerPublisher[*mqModels.MatchStartedModel](pub)
+		}
+
+		if pub, err := newPublisher[*mqModels.MatchFilledModel](
+			config.GetString("RABBITMQ_USERNAME"),
+			config.GetString("RABBITMQ_PASSWORD"),
+			config.GetString("RABBITMQ_HOST"),
+			config.GetString("RABBITMQ_VIRTUAL_HOST"),
+			"matchFilled",
+		); err != nil {
+			logging.Error(ctx, "NewPublisher error %v", err)
+			panic(err)
+		} else {
+			registerPublisher[*mqModels.MatchFilledModel](pub)
+		}
+
+		if pub, err := newPublisher[*mqModels.MatchFailedModel](
+			config.GetString("RABBITMQ_USERNAME"),
+			config.GetString("RABBITMQ_PASSWORD"),
+			config.GetString("RABBITMQ_HOST"),
+			config.GetString("RABBITMQ_VIRTUAL_HOST"),
+			"matchFailed",
+		); err != nil {
+			logging.Error(ctx, "NewPublisher error %v", err)
+			panic(err)
+		} else {
+			registerPublisher[*mqModels.MatchFailedModel](pub)
+		}
+
+		if pub, err := newPublisher[*mqModels.MatchRolledBackModel](
+			config.GetString("RABBITMQ_USERNAME"),
+			config.GetString("RABBITMQ_PASSWORD"),
+			config.GetString("RABBITMQ_HOST"),
+			config.GetString("RABBITMQ_VIRTUAL_HOST"),
+			"matchRolledBack",
+		); err != nil {
+			logging.Error(ctx, "NewPublisher error %v", err)
+			panic(err)
+		} else {
+			registerPublisher[*mqModels.MatchRolledBackModel](pub)
+		}
 	}()
 
 	for k, v := range publishers {
 		logging.Info(ctx, "publisher [%s] [*%s] initialized.", k, reflect.TypeOf(v).Elem().Name())
 	}
 
+	// 撮合事件由matchEventRelay從outbox送出
+	matchEvent.Initialize(&matchEvent.Publishers{
+		Started:    GetPublisher[*mqModels.MatchStartedModel],
+		Filled:     GetPublisher[*mqModels.MatchFilledModel],
+		Failed:     GetPublisher[*mqModels.MatchFailedModel],
+		RolledBack: GetPublisher[*mqModels.MatchRolledBackModel],
+	})
+
+	// 撮合失敗的訊息送到死信exchange, 修正問題後以replayDeadLetter重新撮合
+	if err := deadLetter.Initialize(
+		ctx,
+		config.GetString("RABBITMQ_USERNAME"),
+		config.GetString("RABBITMQ_PASSWORD"),
+		config.GetString("RABBITMQ_HOST"),
+		config.GetString("RABBITMQ_VIRTUAL_HOST"),
+		config.GetString("SERVICE_NAME"),
+	); err != nil {
+		logging.Error(ctx, "deadLetter Initialize error %v", err)
+		panic(err)
+	}
+
 	// ==============================
 	// |    register subscribers    |
 	// ==============================
 
-	if sub, err := rabbitmqOpenPosition.SubscribeAndListen(
+	if sub, err := subscribeAndListen(
 		ctx,
 		config.GetString("RABBITMQ_USERNAME"),
 		config.GetString("RABBITMQ_PASSWORD"),
 		config.GetString("RABBITMQ_HOST"),
 		config.GetString("RABBITMQ_VIRTUAL_HOST"),
 		config.GetString("SERVICE_NAME"),
+		"openPosition",
 		matchOpenPosition.MatchOpenPosition,
 	); err != nil {
 		logging.Error(ctx, "SubscribeAndListen error %v", err)
@@ -58,13 +131,14 @@ func Initialize(ctx context.Context) {
 		subscribers = append(subscribers, sub)
 	}
 
-	if sub, err := rabbitmqClosePosition.SubscribeAndListen(
+	if sub, err := subscribeAndListen(
 		ctx,
 		config.GetString("RABBITMQ_USERNAME"),
 		config.GetString("RABBITMQ_PASSWORD"),
 		config.GetString("RABBITMQ_HOST"),
 		config.GetString("RABBITMQ_VIRTUAL_HOST"),
 		config.GetString("SERVICE_NAME"),
+		"closePosition",
 		matchClosePosition.MatchClosePosition,
 	); err != nil {
 		logging.Error(ctx, "SubscribeAndListen error %v", err)
@@ -75,12 +149,35 @@ func Initialize(ctx context.Context) {
 
 }
 
+// StopConsuming 停止接收新訊息, 已收到的訊息仍會繼續撮合
+func StopConsuming(ctx context.Context) {
+	for _, s := range subscribers {
+		if err := s.StopConsuming(); err != nil {
+			logging.Error(ctx, "pubsub StopConsuming error %v", err)
+		}
+	}
+}
+
+// Drain 等待已收到的訊息都撮合完成, 超過ctx期限時回傳ctx的錯誤
+func Drain(ctx context.Context) error {
+	for _, s := range subscribers {
+		select {
+		case <-s.Done():
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
 func Finalize(ctx context.Context) {
 
 	for _, s := range subscribers {
 		s.Close()
 	}
 
+	deadLetter.Finalize(ctx)
+
 	publisherLock.Lock()
 	defer publisherLock.Unlock()
 	for k, v := range publishers {
@@ -92,6 +189,15 @@ func Finalize(ctx context.Context) {
 	}
 }
 
+// Probes 回傳各訂閱的連線檢查, key為queue名稱
+func Probes() map[string]func() error {
+	probes := map[string]func() error{}
+	for _, s := range subscribers {
+		probes[s.GetQueueName()] = s.Probe
+	}
+	return probes
+}
+
 func GetPublisher[T interface{}](ctx context.Context) bePubsub.TPublisher[T] {
 
 	if !publisherLock.TryRLock() {
@@ -0,0 +1,207 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"sync"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-match/service"
+	bePubsub "github.com/paper-trade-chatbot/be-pubsub"
+	"github.com/paper-trade-chatbot/be-pubsub/rabbitmq"
+	beJson "github.com/paper-trade-chatbot/be-pubsub/rabbitmq/json"
+)
+
+// prefetchCount 尚未ack的訊息數量上限
+const prefetchCount = 10
+
+// listener 由be-match自己讀取訊息, 關閉服務時可以停止消費並等待已收到的訊息處理完成
+type listener interface {
+	bePubsub.Pubsub
+	GetQueueName() string
+	StopConsuming() error
+	Done() <-chan struct{}
+	Probe() error
+}
+
+type subscriber[T interface{}] struct {
+	*beJson.SubscriberImpl[T]
+	done     chan struct{}
+	stopping chan struct{}
+	stopOnce sync.Once
+}
+
+// StopConsuming 通知rabbitmq不再送出新訊息, 處理中的訊息會處理完成, 其餘已收到的訊息放回queue
+func (s *subscriber[T]) StopConsuming() error {
+	s.stopOnce.Do(func() {
+		close(s.stopping)
+	})
+	return s.Channel.Cancel(s.GetConsumer(), false)
+}
+
+// Done 已收到的訊息都處理完成時關閉
+func (s *subscriber[T]) Done() <-chan struct{} {
+	return s.done
+}
+
+// Probe be-pubsub不會重新連線, 連線中斷或停止消費時回傳錯誤
+func (s *subscriber[T]) Probe() error {
+	if s.Connection.IsClosed() {
+		return fmt.Errorf("connection of %s is closed", s.GetQueueName())
+	}
+	select {
+	case <-s.done:
+		return fmt.Errorf("%s stopped consuming", s.GetQueueName())
+	default:
+		return nil
+	}
+}
+
+// subscribeAndListen subscribes to an order dataset with be-match's own message model,
+// so fields not yet defined in be-pubsub can be decoded as well.
+//
+// model must be a pointer to a struct, otherwise it won't work
+func subscribeAndListen[T interface{}](ctx context.Context, username, password, host, virualHost, consumer, dataset string, callbacks ...func(context.Context, T) error) (*subscriber[T], error) {
+	if len(callbacks) == 0 {
+		return nil, bePubsub.ListenNullCallback
+	}
+
+	var model T
+	if reflect.ValueOf(model).Type().Kind() != reflect.Pointer {
+		return nil, bePubsub.ListenNotPointer
+	}
+
+	config := &rabbitmq.SubscriberConfig{
+		ConfigImpl: rabbitmq.ConfigImpl{
+			Username:    username,
+			Password:    password,
+			Host:        host,
+			VirtualHost: virualHost,
+			Exported:    rabbitmq.RABBITMQ_EXPORTED_PRIVATE,
+			Domain:      []string{"order"},
+			Dataset:     dataset,
+			ContentType: rabbitmq.RABBITMQ_CONTENT_TYPE_JSON,
+		},
+		Consumer: consumer,
+	}
+
+	// be-pubsub以auto-ack消費, 收到即ack的訊息在關閉服務時會遺失, 改由be-match自己以手動ack消費
+	sub := &beJson.SubscriberImpl[T]{}
+	pubsub, err := rabbitmq.NewPubsub(config, sub)
+	if err != nil {
+		return nil, err
+	}
+	sub.SubscriberImpl = rabbitmq.SubscriberImpl[T]{
+		PubsubImpl:  *pubsub,
+		ListenMutex: &sync.RWMutex{},
+	}
+
+	if _, err := sub.Channel.QueueDeclare(
+		sub.GetQueueName(), // name
+		true,               // durable
+		false,              // delete when unused
+		false,              // exclusive
+		false,              // no-wait
+		nil,                // arguments
+	); err != nil {
+		sub.Close()
+		return nil, err
+	}
+
+	if err := sub.Channel.Qos(
+		prefetchCount, // prefetch count
+		0,             // prefetch size
+		false,         // global
+	); err != nil {
+		sub.Close()
+		return nil, err
+	}
+
+	delivery, err := sub.Channel.Consume(
+		sub.GetQueueName(), // queue
+		sub.GetConsumer(),  // consumer
+		false,              // auto-ack
+		false,              // exclusive
+		false,              // no-local
+		false,              // no-wait
+		nil,                // args
+	)
+	if err != nil {
+		sub.Close()
+		return nil, err
+	}
+	sub.Delivery = delivery
+
+	s := &subscriber[T]{
+		SubscriberImpl: sub,
+		done:           make(chan struct{}),
+		stopping:       make(chan struct{}),
+	}
+	s.Callbacks = append(s.Callbacks, callbacks...)
+
+	go s.listen(ctx)
+	return s, nil
+}
+
+// listen 與be-pubsub的Listen相同依序處理訊息, 處理完成才ack
+// 停止消費後尚未處理的訊息nack放回queue, 由其他實例或重啟後撮合
+func (s *subscriber[T]) listen(ctx context.Context) {
+	defer close(s.done)
+	logging.Info(ctx, "start listening to %s by %s...", s.GetQueueName(), s.GetConsumer())
+
+	// 停止消費時不再等待斷路器恢復
+	ctxStop, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-s.stopping:
+			cancel()
+		case <-ctxStop.Done():
+		}
+	}()
+
+	for d := range s.Delivery {
+		// 下游服務斷路時暫停消費, 等斷路器恢復後再撮合, 避免訂單一筆筆失敗
+		if err := service.WaitAvailable(ctxStop); err != nil {
+			if err := d.Nack(false, true); err != nil {
+				logging.Error(ctx, "[%s] failed to requeue message: %v", s.GetQueueName(), err)
+			}
+			continue
+		}
+
+		for _, callback := range s.Callbacks {
+			s.handle(ctx, d.Body, callback)
+		}
+		if err := d.Ack(false); err != nil {
+			logging.Error(ctx, "[%s] failed to ack message: %v", s.GetQueueName(), err)
+		}
+	}
+	logging.Info(ctx, "subscriber [%s][%s] terminated.", s.GetQueueName(), s.GetConsumer())
+}
+
+func (s *subscriber[T]) handle(ctx context.Context, body []byte, callback func(context.Context, T) error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logging.Error(ctx, "\x1b[31m%v\n[Stack Trace]\n%s\x1b[m", r, debug.Stack())
+		}
+	}()
+
+	var model T
+	model = reflect.New(reflect.TypeOf(model).Elem()).Interface().(T)
+	if err := json.Unmarshal(body, model); err != nil {
+		logging.Error(ctx, "[%s] failed to unmarshal: %v, message: %s", s.GetQueueName(), err, string(body))
+		return
+	}
+	if _, err := govalidator.ValidateStruct(model); err != nil {
+		logging.Error(ctx, "[%s] ValidateStruct err: %v, message: %s", s.GetQueueName(), err, string(body))
+		return
+	}
+
+	if err := callback(ctx, model); err != nil {
+		logging.Error(ctx, "[%s] callback err: %v", s.GetQueueName(), err)
+	}
+}
@@ -0,0 +1,190 @@
+package matchService
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	common "github.com/paper-trade-chatbot/be-common"
+	"github.com/paper-trade-chatbot/be-common/database"
+	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-common/pagination"
+	"github.com/paper-trade-chatbot/be-match/dao/matchRecordDao"
+	"github.com/paper-trade-chatbot/be-match/lib/matchError"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/paper-trade-chatbot/be-match/proto/match"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// MatchServiceImpl 供聊天機器人及後台查詢撮合紀錄
+type MatchServiceImpl struct {
+}
+
+func New() match.MatchServiceServer {
+	return &MatchServiceImpl{}
+}
+
+// GetMatchRecord 以撮合紀錄id或訂單id查詢單筆撮合紀錄, 以訂單id查詢時不含附加單
+func (impl *MatchServiceImpl) GetMatchRecord(ctx context.Context, req *match.GetMatchRecordReq) (*match.GetMatchRecordRes, error) {
+	var get func(db *gorm.DB) (*dbModels.MatchRecordModel, error)
+	switch r := req.MatchRecord.(type) {
+	case *match.GetMatchRecordReq_Id:
+		if r.Id == 0 {
+			return nil, common.ErrNoQueryCondition
+		}
+		get = func(db *gorm.DB) (*dbModels.MatchRecordModel, error) {
+			return matchRecordDao.Get(db, &matchRecordDao.QueryModel{
+				ID: r.Id,
+			})
+		}
+	case *match.GetMatchRecordReq_Order:
+		if r.Order == nil || r.Order.OrderID == 0 {
+			return nil, common.ErrNoQueryCondition
+		}
+		get = func(db *gorm.DB) (*dbModels.MatchRecordModel, error) {
+			return matchRecordDao.GetByOrder(db, r.Order.OrderID, dbModels.TransactionType(r.Order.TransactionType))
+		}
+	default:
+		return nil, common.ErrNoQueryCondition
+	}
+
+	matchRecord, err := get(database.GetDB())
+	if err != nil {
+		logging.Error(ctx, "[GetMatchRecord] failed to get matchRecord: %v", err)
+		return nil, common.ErrInternal
+	}
+	if matchRecord == nil {
+		return nil, matchError.ErrMatchRecordNotFound
+	}
+
+	return &match.GetMatchRecordRes{
+		MatchRecord: toMatchRecord(matchRecord),
+	}, nil
+}
+
+// GetMatchRecords 依條件分頁查詢撮合紀錄, 新的在前
+func (impl *MatchServiceImpl) GetMatchRecords(ctx context.Context, req *match.GetMatchRecordsReq) (*match.GetMatchRecordsRes, error) {
+	paginate := req.Pagination
+	if paginate == nil {
+		paginate = pagination.NewPagination(defaultPageSize)
+	}
+	if paginate.Page < 1 || paginate.PageSize < 1 || paginate.PageSize > maxPageSize {
+		return nil, common.ErrInvalidParam
+	}
+
+	query := &matchRecordDao.QueryModel{
+		IDs:         req.Id,
+		MemberIDs:   req.MemberID,
+		OrderIDs:    req.OrderID,
+		PositionIDs: req.PositionID,
+	}
+	if req.ExchangeCode != nil {
+		query.ExchangeCode = *req.ExchangeCode
+	}
+	if req.ProductCode != nil {
+		query.ProductCode = *req.ProductCode
+	}
+	for _, s := range req.MatchStatus {
+		query.MatchStatuses = append(query.MatchStatuses, dbModels.MatchStatus(s))
+	}
+	if req.TransactionType != nil {
+		query.TransactionType = dbModels.TransactionType(*req.TransactionType)
+	}
+	if req.LegType != nil {
+		legType := dbModels.LegType(*req.LegType)
+		query.LegType = &legType
+	}
+	if req.CreatedFrom != nil {
+		createdFrom := time.Unix(*req.CreatedFrom, 0)
+		query.CreatedFrom = &createdFrom
+	}
+	if req.CreatedTo != nil {
+		createdTo := time.Unix(*req.CreatedTo, 0)
+		query.CreatedTo = &createdTo
+	}
+
+	matchRecords, paginationInfo, err := matchRecordDao.GetsWithPagination(database.GetDB(), query, paginate)
+	if err != nil {
+		logging.Error(ctx, "[GetMatchRecords] failed to get matchRecords: %v", err)
+		return nil, common.ErrInternal
+	}
+
+	res := &match.GetMatchRecordsRes{
+		MatchRecords:   make([]*match.MatchRecord, 0, len(matchRecords)),
+		PaginationInfo: paginationInfo,
+	}
+	for i := range matchRecords {
+		res.MatchRecords = append(res.MatchRecords, toMatchRecord(&matchRecords[i]))
+	}
+	return res, nil
+}
+
+func toMatchRecord(m *dbModels.MatchRecordModel) *match.MatchRecord {
+	return &match.MatchRecord{
+		Id:               m.ID,
+		OrderID:          m.OrderID,
+		MemberID:         m.MemberID,
+		PositionID:       nullID(m.PositionID),
+		MatchStatus:      match.MatchStatus(m.MatchStatus),
+		TransactionType:  match.TransactionType(m.TransactionType),
+		ExchangeCode:     m.ExchangeCode,
+		ProductCode:      m.ProductCode,
+		TradeType:        match.TradeType(m.TradeType),
+		OrderType:        match.OrderType(m.OrderType),
+		LimitPrice:       nullDecimal(m.LimitPrice),
+		LegType:          match.LegType(m.LegType),
+		LegOrderID:       nullID(m.LegOrderID),
+		TimeInForce:      match.TimeInForce(m.TimeInForce),
+		ExpireAt:         nullTime(m.ExpireAt),
+		OpenPrice:        nullDecimal(m.OpenPrice),
+		ClosePrice:       nullDecimal(m.ClosePrice),
+		Amount:           m.Amount.String(),
+		TransactionID:    nullID(m.TransactionID),
+		Fee:              m.Fee.String(),
+		FeeTransactionID: nullID(m.FeeTransactionID),
+		Leverage:         m.Leverage.String(),
+		Margin:           nullDecimal(m.Margin),
+		CreatedAt:        m.CreatedAt.Unix(),
+		UpdatedAt:        m.UpdatedAt.Unix(),
+		ExpireOutcome:    match.ExpireOutcome(m.ExpireOutcome),
+		PricingModel:     match.PricingModel(m.PricingModel),
+		QuoteAsk:         nullDecimal(m.QuoteAsk),
+		QuoteBid:         nullDecimal(m.QuoteBid),
+		QuoteTime:        nullTime(m.QuoteTime),
+		StaleQuote:       m.StaleQuote,
+		RetryCount:       int32(m.RetryCount),
+		RetryOutcome:     match.RetryOutcome(m.RetryOutcome),
+		RetryError:       m.RetryError,
+		SagaStep:         match.SagaStep(m.SagaStep),
+	}
+}
+
+func nullID(id sql.NullInt64) *uint64 {
+	if !id.Valid {
+		return nil
+	}
+	v := uint64(id.Int64)
+	return &v
+}
+
+func nullDecimal(d decimal.NullDecimal) *string {
+	if !d.Valid {
+		return nil
+	}
+	v := d.Decimal.String()
+	return &v
+}
+
+func nullTime(t sql.NullTime) *int64 {
+	if !t.Valid {
+		return nil
+	}
+	v := t.Time.Unix()
+	return &v
+}
@@ -0,0 +1,41 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/paper-trade-chatbot/be-common/config"
+	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-common/server"
+	"github.com/paper-trade-chatbot/be-match/proto/match"
+	"github.com/paper-trade-chatbot/be-match/rpc/matchService"
+	"google.golang.org/grpc"
+)
+
+var grpcServer *grpc.Server
+
+// Initialize 註冊be-match的gRPC服務並開始監聽
+func Initialize(ctx context.Context) {
+	address := fmt.Sprintf("%s:%s",
+		config.GetString("SERVER_LISTEN_ADDRESS"),
+		config.GetString("GRPC_SERVER_LISTEN_PORT"))
+	listener := server.CreateGRpcServer(ctx, address)
+
+	grpcServer = grpc.NewServer()
+	match.RegisterMatchServiceServer(grpcServer, matchService.New())
+
+	go func() {
+		if err := grpcServer.Serve(*listener); err != nil {
+			logging.Error(ctx, "grpc Serve error %v", err)
+		}
+	}()
+}
+
+// Finalize 等進行中的查詢完成後關閉gRPC服務
+func Finalize(ctx context.Context) {
+	if grpcServer == nil {
+		return
+	}
+	grpcServer.GracefulStop()
+	grpcServer = nil
+}
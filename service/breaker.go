@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/paper-trade-chatbot/be-common/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type BreakerState int
+
+const (
+	BreakerState_Closed   BreakerState = iota // 正常呼叫
+	BreakerState_Open                         // 暫停呼叫
+	BreakerState_HalfOpen                     // 放行一次呼叫試探服務是否恢復
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerState_Open:
+		return "open"
+	case BreakerState_HalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+const (
+	// breakerFailureThreshold 連續失敗達此次數時斷路
+	breakerFailureThreshold = 5
+	// breakerOpenDuration 斷路後經過此時間才放行試探的呼叫
+	breakerOpenDuration = 30 * time.Second
+	// breakerWaitInterval 等待斷路器恢復時的檢查間隔
+	breakerWaitInterval = time.Second
+)
+
+// breakerFailureCodes 只有服務本身異常才計入失敗, 業務錯誤例如餘額不足不影響斷路器
+var breakerFailureCodes = map[codes.Code]bool{
+	codes.Unavailable:       true,
+	codes.DeadlineExceeded:  true,
+	codes.ResourceExhausted: true,
+}
+
+type breaker struct {
+	sync.Mutex
+	name     string
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+var (
+	breakers     = map[string]*breaker{}
+	breakerNames = []string{}
+	breakersLock sync.RWMutex
+)
+
+func newBreaker(name string) *breaker {
+	breakersLock.Lock()
+	defer breakersLock.Unlock()
+
+	if b, ok := breakers[name]; ok {
+		return b
+	}
+	b := &breaker{name: name}
+	breakers[name] = b
+	breakerNames = append(breakerNames, name)
+	return b
+}
+
+// allow 斷路中回傳false, 斷路時間已過時只放行一個試探的呼叫
+func (b *breaker) allow() bool {
+	b.Lock()
+	defer b.Unlock()
+
+	switch b.state {
+	case BreakerState_Open:
+		if time.Since(b.openedAt) < breakerOpenDuration {
+			return false
+		}
+		b.state = BreakerState_HalfOpen
+		b.probing = true
+		return true
+	case BreakerState_HalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *breaker) record(ctx context.Context, err error) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.probing = false
+	if err == nil || !breakerFailureCodes[status.Code(err)] {
+		if b.state != BreakerState_Closed {
+			logging.Info(ctx, "[Breaker] %s closed.", b.name)
+		}
+		b.state = BreakerState_Closed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == BreakerState_HalfOpen || b.failures >= breakerFailureThreshold {
+		if b.state != BreakerState_Open {
+			logging.Warn(ctx, "[Breaker] %s opened after %d failures: %v", b.name, b.failures, err)
+		}
+		b.state = BreakerState_Open
+		b.openedAt = time.Now()
+	}
+}
+
+// available 斷路中且尚未到試探時間時回傳false
+func (b *breaker) available() bool {
+	b.Lock()
+	defer b.Unlock()
+	return b.state == BreakerState_Closed ||
+		(b.state == BreakerState_Open && time.Since(b.openedAt) >= breakerOpenDuration) ||
+		(b.state == BreakerState_HalfOpen && !b.probing)
+}
+
+// breakerInterceptor 每個下游服務各自一個斷路器, 斷路中直接回傳Unavailable
+func breakerInterceptor(name string) grpc.UnaryClientInterceptor {
+	b := newBreaker(name)
+	return func(ctx context.Context, method string, req interface{}, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !b.allow() {
+			return status.Errorf(codes.Unavailable, "circuit breaker of %s is open", name)
+		}
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		b.record(ctx, err)
+		return err
+	}
+}
+
+// BreakerStates 回傳各下游服務斷路器的狀態
+func BreakerStates() map[string]BreakerState {
+	breakersLock.RLock()
+	defer breakersLock.RUnlock()
+
+	states := map[string]BreakerState{}
+	for name, b := range breakers {
+		b.Lock()
+		states[name] = b.state
+		b.Unlock()
+	}
+	return states
+}
+
+// Available 所有斷路器都可以呼叫時回傳true
+func Available() bool {
+	breakersLock.RLock()
+	defer breakersLock.RUnlock()
+
+	for _, name := range breakerNames {
+		if !breakers[name].available() {
+			return false
+		}
+	}
+	return true
+}
+
+// WaitAvailable 等到所有斷路器都可以呼叫, 撮合前呼叫以暫停消費而不是讓訂單失敗
+func WaitAvailable(ctx context.Context) error {
+	if Available() {
+		return nil
+	}
+	logging.Warn(ctx, "[Breaker] circuit breaker open, pause matching.")
+
+	ticker := time.NewTicker(breakerWaitInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if Available() {
+				logging.Info(ctx, "[Breaker] resume matching.")
+				return nil
+			}
+		}
+	}
+}
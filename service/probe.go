@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+type conn struct {
+	conn    *grpc.ClientConn
+	dialErr error
+}
+
+var (
+	conns     = map[string]*conn{}
+	connNames = []string{}
+	connsLock sync.RWMutex
+)
+
+// registerConn 記下各下游服務的連線及建立連線時的錯誤, 供健康檢查使用
+func registerConn(name string, c *grpc.ClientConn, dialErr error) {
+	connsLock.Lock()
+	defer connsLock.Unlock()
+
+	if _, ok := conns[name]; !ok {
+		connNames = append(connNames, name)
+	}
+	conns[name] = &conn{
+		conn:    c,
+		dialErr: dialErr,
+	}
+}
+
+// ConnNames 回傳各下游服務的名稱, 依連線建立的順序
+func ConnNames() []string {
+	connsLock.RLock()
+	defer connsLock.RUnlock()
+	return append([]string{}, connNames...)
+}
+
+// Probe 主動建立與下游服務的連線, 在ctx期限內連線就緒時回傳nil
+func Probe(ctx context.Context, name string) error {
+	connsLock.RLock()
+	c, ok := conns[name]
+	connsLock.RUnlock()
+	if !ok {
+		return fmt.Errorf("%s not initialized", name)
+	}
+	if c.dialErr != nil {
+		return c.dialErr
+	}
+
+	for {
+		state := c.conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.Shutdown:
+			return fmt.Errorf("connection to %s is shut down", name)
+		case connectivity.Idle:
+			c.conn.Connect()
+		}
+		if !c.conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("connection to %s is %s: %w", name, state, ctx.Err())
+		}
+	}
+}
@@ -59,10 +59,10 @@ type ServiceImpl struct {
 	PositionIntf position.PositionIntf
 }
 
-func GrpcDial(addr string) (*grpc.ClientConn, error) {
+func GrpcDial(name, addr string) (*grpc.ClientConn, error) {
 	return grpc.Dial(addr, grpc.WithInsecure(), grpc.WithDefaultCallOptions(
 		grpc.MaxCallRecvMsgSize(20*1024*1024),
-		grpc.MaxCallSendMsgSize(20*1024*1024)), grpc.WithUnaryInterceptor(clientInterceptor))
+		grpc.MaxCallSendMsgSize(20*1024*1024)), grpc.WithChainUnaryInterceptor(clientInterceptor, breakerInterceptor(name)))
 }
 
 func Initialize(ctx context.Context) {
@@ -71,60 +71,66 @@ func Initialize(ctx context.Context) {
 
 	addr := MemberServiceHost + ":" + MemberServerGRpcPort
 	fmt.Println("dial to order grpc server...", addr)
-	memberServiceConn, err = GrpcDial(addr)
+	memberServiceConn, err = GrpcDial("member", addr)
 	if err != nil {
 		fmt.Println("Can not connect to gRPC server:", err)
 	}
+	registerConn("member", memberServiceConn, err)
 	fmt.Println("dial done")
 	memberConn := memberGrpc.NewMemberServiceClient(memberServiceConn)
 	Impl.MemberIntf = member.New(memberConn)
 
 	addr = ProductServiceHost + ":" + ProductServerGRpcPort
 	fmt.Println("dial to order grpc server...", addr)
-	productServiceConn, err = GrpcDial(addr)
+	productServiceConn, err = GrpcDial("product", addr)
 	if err != nil {
 		fmt.Println("Can not connect to gRPC server:", err)
 	}
+	registerConn("product", productServiceConn, err)
 	fmt.Println("dial done")
 	productConn := productGrpc.NewProductServiceClient(productServiceConn)
 	Impl.ProductIntf = product.New(productConn)
 
 	addr = OrderServiceHost + ":" + OrderServerGRpcPort
 	fmt.Println("dial to order grpc server...", addr)
-	orderServiceConn, err = GrpcDial(addr)
+	orderServiceConn, err = GrpcDial("order", addr)
 	if err != nil {
 		fmt.Println("Can not connect to gRPC server:", err)
 	}
+	registerConn("order", orderServiceConn, err)
 	fmt.Println("dial done")
 	orderConn := orderGrpc.NewOrderServiceClient(orderServiceConn)
 	Impl.OrderIntf = order.New(orderConn)
 
 	addr = WalletServiceHost + ":" + WalletServerGRpcPort
 	fmt.Println("dial to order grpc server...", addr)
-	walletServiceConn, err = GrpcDial(addr)
+	walletServiceConn, err = GrpcDial("wallet", addr)
 	if err != nil {
 		fmt.Println("Can not connect to gRPC server:", err)
 	}
+	registerConn("wallet", walletServiceConn, err)
 	fmt.Println("dial done")
 	walletConn := walletGrpc.NewWalletServiceClient(walletServiceConn)
 	Impl.WalletIntf = wallet.New(walletConn)
 
 	addr = QuoteServiceHost + ":" + QuoteServerGRpcPort
 	fmt.Println("dial to order grpc server...", addr)
-	quoteServiceConn, err = GrpcDial(addr)
+	quoteServiceConn, err = GrpcDial("quote", addr)
 	if err != nil {
 		fmt.Println("Can not connect to gRPC server:", err)
 	}
+	registerConn("quote", quoteServiceConn, err)
 	fmt.Println("dial done")
 	quoteConn := quoteGrpc.NewQuoteServiceClient(quoteServiceConn)
 	Impl.QuoteIntf = quote.New(quoteConn)
 
 	addr = PositionServiceHost + ":" + PositionServerGRpcPort
 	fmt.Println("dial to order grpc server...", addr)
-	positionServiceConn, err = GrpcDial(addr)
+	positionServiceConn, err = GrpcDial("position", addr)
 	if err != nil {
 		fmt.Println("Can not connect to gRPC server:", err)
 	}
+	registerConn("position", positionServiceConn, err)
 	fmt.Println("dial done")
 	positionConn := positionGrpc.NewPositionServiceClient(positionServiceConn)
 	Impl.PositionIntf = position.New(positionConn)
@@ -136,6 +142,7 @@ func Finalize(ctx context.Context) {
 	orderServiceConn.Close()
 	walletServiceConn.Close()
 	quoteServiceConn.Close()
+	positionServiceConn.Close()
 }
 
 func clientInterceptor(ctx context.Context, method string, req interface{}, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
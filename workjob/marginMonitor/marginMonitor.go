@@ -0,0 +1,258 @@
+package marginMonitor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	common "github.com/paper-trade-chatbot/be-common"
+	"github.com/paper-trade-chatbot/be-common/cache"
+	"github.com/paper-trade-chatbot/be-common/database"
+	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-match/dao/marginCallDao"
+	"github.com/paper-trade-chatbot/be-match/dao/positionMarginDao"
+	"github.com/paper-trade-chatbot/be-match/lib/forceClose"
+	"github.com/paper-trade-chatbot/be-match/lib/margin"
+	"github.com/paper-trade-chatbot/be-match/lib/marketPrice"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/paper-trade-chatbot/be-match/service"
+	"github.com/paper-trade-chatbot/be-proto/product"
+	"github.com/paper-trade-chatbot/be-proto/wallet"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	checkInterval      = 10 * time.Second
+	marginCallInterval = time.Hour
+)
+
+// account 保證金以會員及幣別為單位計算
+type account struct {
+	memberID     uint64
+	currencyCode string
+}
+
+// holding 倉位以市價計算後的權益及保證金需求
+type holding struct {
+	positionMargin    dbModels.PositionMarginModel
+	profitAndLoss     decimal.Decimal
+	maintenanceMargin decimal.Decimal
+	liquidationMargin decimal.Decimal
+}
+
+// MarginMonitor 定期以最新報價計算帳戶權益
+// 權益低於維持保證金時發出追繳, 低於強制平倉保證金時以關倉流程強制平倉
+func MarginMonitor(ctx context.Context) error {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			checkAccounts(ctx)
+		}
+	}
+}
+
+func checkAccounts(ctx context.Context) {
+	// 下游服務斷路時暫停, 等恢復後再處理
+	if !service.Available() {
+		return
+	}
+
+	db := database.GetDB()
+
+	positionMargins, err := positionMarginDao.Gets(db, &positionMarginDao.QueryModel{
+		MarginStatus: dbModels.MarginStatus_Open,
+	})
+	if err != nil {
+		logging.Error(ctx, "[MarginMonitor] failed to get positionMargins: %v", err)
+		return
+	}
+
+	products := map[string]*product.Product{}
+	prices := map[string]decimal.Decimal{}
+	rules := map[dbModels.ProductType]*dbModels.MarginRuleModel{}
+	accounts := []account{}
+	holdings := map[account][]*holding{}
+	// 任一倉位無法估值時整個會員本輪略過, 少算該倉位的保證金會低估權益而誤平其他倉位
+	skipped := map[uint64]bool{}
+	for _, pm := range positionMargins {
+		if skipped[pm.MemberID] {
+			continue
+		}
+		key := fmt.Sprintf("%s:%s", pm.ExchangeCode, pm.ProductCode)
+		p, ok := products[key]
+		if !ok {
+			p, err = marketPrice.GetProduct(ctx, pm.ExchangeCode, pm.ProductCode)
+			if err != nil {
+				logging.Warn(ctx, "[MarginMonitor] failed to get product [%s][%s]: %v", pm.ExchangeCode, pm.ProductCode, err)
+				skipped[pm.MemberID] = true
+				continue
+			}
+			products[key] = p
+		}
+
+		// 與關倉撮合取相同方向的報價
+		priceKey := fmt.Sprintf("%s:%d", key, pm.TradeType)
+		unitPrice, ok := prices[priceKey]
+		if !ok {
+			unitPrice, err = marketPrice.GetPrice(ctx, p.Id, pm.TradeType, dbModels.TransactionType_ClosePosition)
+			if err != nil {
+				logging.Warn(ctx, "[MarginMonitor] failed to get price [%s][%s]: %v", pm.ExchangeCode, pm.ProductCode, err)
+				skipped[pm.MemberID] = true
+				continue
+			}
+			prices[priceKey] = unitPrice
+		}
+
+		productType := dbModels.ProductType(p.Type)
+		rule, ok := rules[productType]
+		if !ok {
+			rule, err = margin.GetRule(db, productType)
+			if err != nil {
+				logging.Error(ctx, "[MarginMonitor] failed to get margin rule [%d]: %v", productType, err)
+				skipped[pm.MemberID] = true
+				continue
+			}
+			rules[productType] = rule
+		}
+
+		notional := unitPrice.Mul(pm.Amount)
+		a := account{memberID: pm.MemberID, currencyCode: p.CurrencyCode}
+		if _, ok := holdings[a]; !ok {
+			accounts = append(accounts, a)
+		}
+		holdings[a] = append(holdings[a], &holding{
+			positionMargin:    pm,
+			profitAndLoss:     margin.ProfitAndLoss(pm.TradeType, pm.OpenPrice, unitPrice, pm.Amount),
+			maintenanceMargin: notional.Mul(rule.MaintenanceRate),
+			liquidationMargin: notional.Mul(rule.LiquidationRate),
+		})
+	}
+
+	for _, a := range accounts {
+		if skipped[a.memberID] {
+			logging.Warn(ctx, "[MarginMonitor] member[%d] skipped, not all positions can be valued.", a.memberID)
+			continue
+		}
+		checkAccount(ctx, db, a, holdings[a])
+	}
+}
+
+func checkAccount(ctx context.Context, db *gorm.DB, a account, holdings []*holding) {
+	walletRes, err := service.Impl.WalletIntf.GetWallets(ctx, &wallet.GetWalletsReq{
+		Wallet: &wallet.GetWalletsReq_MemberID{
+			MemberID: a.memberID,
+		},
+		Currency: &a.currencyCode,
+	})
+	if err != nil || len(walletRes.Wallets) == 0 {
+		logging.Warn(ctx, "[MarginMonitor] failed to get wallet by member[%d] currency[%s]: %v", a.memberID, a.currencyCode, err)
+		return
+	}
+	balance, err := decimal.NewFromString(walletRes.Wallets[0].Amount)
+	if err != nil {
+		logging.Error(ctx, "[MarginMonitor] NewFromString failed: %v", err)
+		return
+	}
+
+	// 帳戶權益 = 錢包餘額 + 各倉位占用的保證金 + 未實現損益
+	equity := balance
+	maintenanceMargin := decimal.Zero
+	liquidationMargin := decimal.Zero
+	for _, h := range holdings {
+		equity = equity.Add(h.positionMargin.Margin).Add(h.profitAndLoss)
+		maintenanceMargin = maintenanceMargin.Add(h.maintenanceMargin)
+		liquidationMargin = liquidationMargin.Add(h.liquidationMargin)
+	}
+
+	if equity.GreaterThanOrEqual(maintenanceMargin) {
+		return
+	}
+
+	if equity.GreaterThanOrEqual(liquidationMargin) {
+		marginCall(ctx, db, a, equity, maintenanceMargin, liquidationMargin)
+		return
+	}
+
+	liquidate(ctx, db, a, holdings, equity, maintenanceMargin, liquidationMargin)
+}
+
+func marginCall(ctx context.Context, db *gorm.DB, a account, equity, maintenanceMargin, liquidationMargin decimal.Decimal) {
+	// 同一帳戶在marginCallInterval內只追繳一次
+	r, _ := cache.GetRedis()
+	key := fmt.Sprintf("marginMonitor:marginCall:%d:%s", a.memberID, a.currencyCode)
+	if flag, _ := r.SetNX(ctx, key, equity.String(), marginCallInterval).Result(); !flag {
+		return
+	}
+
+	logging.Warn(ctx, "[MarginMonitor] margin call member[%d] currency[%s]: equity %s, maintenance margin %s",
+		a.memberID, a.currencyCode, equity.String(), maintenanceMargin.String())
+	if _, err := marginCallDao.New(db, &dbModels.MarginCallModel{
+		MemberID:          a.memberID,
+		CurrencyCode:      a.currencyCode,
+		MarginCallType:    dbModels.MarginCallType_MarginCall,
+		Equity:            equity,
+		MaintenanceMargin: maintenanceMargin,
+		LiquidationMargin: liquidationMargin,
+	}); err != nil {
+		logging.Error(ctx, "[MarginMonitor] failed to new marginCall: %v", err)
+	}
+}
+
+// liquidate 由虧損最多的倉位開始強制平倉, 直到權益回到維持保證金以上
+func liquidate(ctx context.Context, db *gorm.DB, a account, holdings []*holding, equity, maintenanceMargin, liquidationMargin decimal.Decimal) {
+	sort.SliceStable(holdings, func(i, j int) bool {
+		return holdings[i].profitAndLoss.LessThan(holdings[j].profitAndLoss)
+	})
+
+	for _, h := range holdings {
+		if equity.GreaterThanOrEqual(maintenanceMargin) {
+			return
+		}
+
+		positionID := h.positionMargin.PositionID
+		logging.Warn(ctx, "[MarginMonitor] liquidate position [%d] of member[%d] currency[%s]: equity %s, liquidation margin %s",
+			positionID, a.memberID, a.currencyCode, equity.String(), liquidationMargin.String())
+
+		matchRecord, err := forceClose.ClosePosition(ctx, positionID, dbModels.TransactionType_Liquidation)
+		if errors.Is(err, common.ErrNoSuchPosition) {
+			// 倉位已關閉, 保證金不需再計入
+			marginStatus := dbModels.MarginStatus_Released
+			if err := positionMarginDao.Modify(db, &h.positionMargin, &positionMarginDao.UpdateModel{
+				MarginStatus: &marginStatus,
+			}); err != nil {
+				logging.Error(ctx, "[MarginMonitor] failed to Modify positionMargin [%d]: %v", positionID, err)
+			}
+			continue
+		}
+		if err != nil || matchRecord == nil {
+			logging.Error(ctx, "[MarginMonitor] failed to liquidate position [%d]: %v", positionID, err)
+			continue
+		}
+
+		if _, err := marginCallDao.New(db, &dbModels.MarginCallModel{
+			MemberID:          a.memberID,
+			CurrencyCode:      a.currencyCode,
+			MarginCallType:    dbModels.MarginCallType_Liquidation,
+			Equity:            equity,
+			MaintenanceMargin: maintenanceMargin,
+			LiquidationMargin: liquidationMargin,
+			PositionID:        sql.NullInt64{Valid: true, Int64: int64(positionID)},
+			MatchRecordID:     sql.NullInt64{Valid: true, Int64: int64(matchRecord.ID)},
+		}); err != nil {
+			logging.Error(ctx, "[MarginMonitor] failed to new marginCall: %v", err)
+		}
+
+		// 平倉後損益已實現, 權益不變, 只需扣除該倉位的保證金需求
+		maintenanceMargin = maintenanceMargin.Sub(h.maintenanceMargin)
+		liquidationMargin = liquidationMargin.Sub(h.liquidationMargin)
+	}
+}
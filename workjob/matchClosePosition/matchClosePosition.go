@@ -0,0 +1,116 @@
+package matchClosePosition
+
+import (
+	"context"
+	"time"
+
+	"github.com/paper-trade-chatbot/be-common/database"
+	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-match/dao/pendingOrderDao"
+	"github.com/paper-trade-chatbot/be-match/lib/inflight"
+	"github.com/paper-trade-chatbot/be-match/lib/limitOrder"
+	"github.com/paper-trade-chatbot/be-match/lib/marketPrice"
+	"github.com/paper-trade-chatbot/be-match/lib/tradingCalendar"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/paper-trade-chatbot/be-match/models/mqModels"
+	pubsubMatchClosePosition "github.com/paper-trade-chatbot/be-match/pubsub/matchClosePosition"
+	"github.com/paper-trade-chatbot/be-match/service"
+	"github.com/paper-trade-chatbot/be-pubsub/order/closePosition/rabbitmq"
+	"github.com/shopspring/decimal"
+)
+
+const checkInterval = 3 * time.Second
+
+// MatchClosePosition 定期以最新報價檢查關倉掛單, 價格穿越限價時重新送進撮合
+func MatchClosePosition(ctx context.Context) error {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			matchPendingOrders(ctx)
+		}
+	}
+}
+
+func matchPendingOrders(ctx context.Context) {
+	// 下游服務斷路時暫停, 等恢復後再處理
+	if !service.Available() {
+		return
+	}
+
+	db := database.GetDB()
+
+	pendingOrders, err := pendingOrderDao.Gets(db, &pendingOrderDao.QueryModel{
+		PendingStatus:   dbModels.PendingStatus_Pending,
+		TransactionType: dbModels.TransactionType_ClosePosition,
+	})
+	if err != nil {
+		logging.Error(ctx, "[MatchClosePosition] failed to get pendingOrders: %v", err)
+		return
+	}
+
+	for _, p := range pendingOrders {
+		// 休市中的掛單等開盤再撮合
+		marketOpen, err := tradingCalendar.IsOpen(ctx, p.ExchangeCode, time.Now())
+		if err != nil {
+			logging.Warn(ctx, "[MatchClosePosition] failed to check trading calendar [%s]: %v", p.ExchangeCode, err)
+			continue
+		}
+		if !marketOpen {
+			continue
+		}
+
+		productRes, err := marketPrice.GetProduct(ctx, p.ExchangeCode, p.ProductCode)
+		if err != nil {
+			logging.Warn(ctx, "[MatchClosePosition] failed to get product [%s][%s]: %v", p.ExchangeCode, p.ProductCode, err)
+			continue
+		}
+
+		unitPrice, err := marketPrice.GetPrice(ctx, productRes.Id, p.TradeType, p.TransactionType)
+		if err != nil {
+			logging.Warn(ctx, "[MatchClosePosition] failed to get price [%s][%s]: %v", p.ExchangeCode, p.ProductCode, err)
+			continue
+		}
+
+		// 市價單只會因斷路暫停而掛單, 恢復後直接撮合
+		if p.OrderType != dbModels.OrderType_Market &&
+			!limitOrder.IsMarketable(p.TradeType, p.TransactionType, unitPrice, p.LimitPrice) {
+			continue
+		}
+
+		var expireAt *int64
+		if p.ExpireAt.Valid {
+			expireAtUnix := p.ExpireAt.Time.Unix()
+			expireAt = &expireAtUnix
+		}
+
+		// 關閉服務中不再開始新的撮合
+		if !inflight.Enter() {
+			return
+		}
+		err = pubsubMatchClosePosition.MatchClosePosition(ctx, &mqModels.ClosePositionModel{
+			ClosePositionModel: rabbitmq.ClosePositionModel{
+				ID:           p.OrderID,
+				MemberID:     p.MemberID,
+				PositionID:   uint64(p.PositionID.Int64),
+				ExchangeCode: p.ExchangeCode,
+				ProductCode:  p.ProductCode,
+				TradeType:    rabbitmq.TradeType(p.TradeType),
+				OpenPrice:    p.OpenPrice.Decimal,
+				CloseAmount:  p.Amount,
+			},
+			OrderType:   p.OrderType,
+			LimitPrice:  decimal.NewNullDecimal(p.LimitPrice),
+			TimeInForce: p.TimeInForce,
+			ExpireAt:    expireAt,
+		})
+		inflight.Leave()
+		if err != nil {
+			logging.Error(ctx, "[MatchClosePosition] failed to match pendingOrder [%d]: %v", p.OrderID, err)
+		}
+	}
+}
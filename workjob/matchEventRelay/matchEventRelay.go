@@ -0,0 +1,113 @@
+package matchEventRelay
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/paper-trade-chatbot/be-common/database"
+	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-match/dao/matchEventOutboxDao"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/paper-trade-chatbot/be-match/pubsub/matchEvent"
+	"gorm.io/gorm"
+)
+
+const (
+	relayInterval  = time.Second
+	batchSize      = 100
+	maxErrorLength = 255
+	purgeInterval  = time.Hour
+	// retention 已送出的事件保留時間, 供查詢是否送出過
+	retention = 7 * 24 * time.Hour
+)
+
+// MatchEventRelay 定期將outbox中待送出的撮合事件送到rabbitmq並標記為已送出
+// 送出後標記前中斷時會再送一次, 消費端以事件id去除重複
+func MatchEventRelay(ctx context.Context) error {
+	ticker := time.NewTicker(relayInterval)
+	defer ticker.Stop()
+
+	lastPurge := time.Time{}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			// 一次送不完時繼續送下一批
+			for ctx.Err() == nil {
+				if relay(ctx) < batchSize {
+					break
+				}
+			}
+			if time.Since(lastPurge) >= purgeInterval {
+				purge(ctx)
+				lastPurge = time.Now()
+			}
+		}
+	}
+}
+
+// relay 依寫入順序送出一批事件, 遇到送出失敗即停止, 避免同一訂單的事件順序錯亂
+// 回傳送出的數量
+func relay(ctx context.Context) int {
+	sent := 0
+	db := database.GetDB()
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		outboxes, err := matchEventOutboxDao.GetsPendingForUpdate(tx, batchSize)
+		if err != nil {
+			return err
+		}
+
+		for i := range outboxes {
+			outbox := &outboxes[i]
+			attempts := outbox.Attempts + 1
+
+			if err := matchEvent.Relay(ctx, outbox); err != nil {
+				if outbox.Attempts == 0 {
+					logging.Warn(ctx, "[MatchEventRelay] failed to relay event [%s] of order [%d]. retry later: %v", outbox.EventID, outbox.OrderID, err)
+				}
+				lastError := []rune(err.Error())
+				if len(lastError) > maxErrorLength {
+					lastError = lastError[:maxErrorLength]
+				}
+				lastErrorString := string(lastError)
+				return matchEventOutboxDao.Modify(tx, outbox, &matchEventOutboxDao.UpdateModel{
+					Attempts:  &attempts,
+					LastError: &lastErrorString,
+				})
+			}
+
+			outboxStatus := dbModels.OutboxStatus_Sent
+			if err := matchEventOutboxDao.Modify(tx, outbox, &matchEventOutboxDao.UpdateModel{
+				OutboxStatus: &outboxStatus,
+				Attempts:     &attempts,
+				SentAt:       &sql.NullTime{Valid: true, Time: time.Now()},
+			}); err != nil {
+				return err
+			}
+			sent++
+		}
+		return nil
+	}); err != nil {
+		logging.Error(ctx, "[MatchEventRelay] failed to relay events: %v", err)
+		return 0
+	}
+	return sent
+}
+
+// purge 分批刪除超過保留時間的已送出事件
+func purge(ctx context.Context) {
+	db := database.GetDB()
+	sentBefore := time.Now().Add(-retention)
+	for ctx.Err() == nil {
+		deleted, err := matchEventOutboxDao.DeleteSent(db, sentBefore, batchSize)
+		if err != nil {
+			logging.Error(ctx, "[MatchEventRelay] failed to purge sent events: %v", err)
+			return
+		}
+		if deleted < batchSize {
+			return
+		}
+	}
+}
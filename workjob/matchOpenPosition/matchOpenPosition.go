@@ -1,7 +1,114 @@
 package matchOpenPosition
 
-import "context"
+import (
+	"context"
+	"time"
 
+	"github.com/paper-trade-chatbot/be-common/database"
+	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-match/dao/pendingOrderDao"
+	"github.com/paper-trade-chatbot/be-match/lib/inflight"
+	"github.com/paper-trade-chatbot/be-match/lib/limitOrder"
+	"github.com/paper-trade-chatbot/be-match/lib/marketPrice"
+	"github.com/paper-trade-chatbot/be-match/lib/tradingCalendar"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/paper-trade-chatbot/be-match/models/mqModels"
+	pubsubMatchOpenPosition "github.com/paper-trade-chatbot/be-match/pubsub/matchOpenPosition"
+	"github.com/paper-trade-chatbot/be-match/service"
+	"github.com/paper-trade-chatbot/be-pubsub/order/openPosition/rabbitmq"
+	"github.com/shopspring/decimal"
+)
+
+const checkInterval = 3 * time.Second
+
+// MatchOpenPosition 定期以最新報價檢查開倉掛單, 價格穿越限價時重新送進撮合
 func MatchOpenPosition(ctx context.Context) error {
-	return nil
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			matchPendingOrders(ctx)
+		}
+	}
+}
+
+func matchPendingOrders(ctx context.Context) {
+	// 下游服務斷路時暫停, 等恢復後再處理
+	if !service.Available() {
+		return
+	}
+
+	db := database.GetDB()
+
+	pendingOrders, err := pendingOrderDao.Gets(db, &pendingOrderDao.QueryModel{
+		PendingStatus:   dbModels.PendingStatus_Pending,
+		TransactionType: dbModels.TransactionType_OpenPosition,
+	})
+	if err != nil {
+		logging.Error(ctx, "[MatchOpenPosition] failed to get pendingOrders: %v", err)
+		return
+	}
+
+	for _, p := range pendingOrders {
+		// 休市中的掛單等開盤再撮合
+		marketOpen, err := tradingCalendar.IsOpen(ctx, p.ExchangeCode, time.Now())
+		if err != nil {
+			logging.Warn(ctx, "[MatchOpenPosition] failed to check trading calendar [%s]: %v", p.ExchangeCode, err)
+			continue
+		}
+		if !marketOpen {
+			continue
+		}
+
+		productRes, err := marketPrice.GetProduct(ctx, p.ExchangeCode, p.ProductCode)
+		if err != nil {
+			logging.Warn(ctx, "[MatchOpenPosition] failed to get product [%s][%s]: %v", p.ExchangeCode, p.ProductCode, err)
+			continue
+		}
+
+		unitPrice, err := marketPrice.GetPrice(ctx, productRes.Id, p.TradeType, p.TransactionType)
+		if err != nil {
+			logging.Warn(ctx, "[MatchOpenPosition] failed to get price [%s][%s]: %v", p.ExchangeCode, p.ProductCode, err)
+			continue
+		}
+
+		// 市價單只會因斷路暫停而掛單, 恢復後直接撮合
+		if p.OrderType != dbModels.OrderType_Market &&
+			!limitOrder.IsMarketable(p.TradeType, p.TransactionType, unitPrice, p.LimitPrice) {
+			continue
+		}
+
+		var expireAt *int64
+		if p.ExpireAt.Valid {
+			expireAtUnix := p.ExpireAt.Time.Unix()
+			expireAt = &expireAtUnix
+		}
+
+		// 關閉服務中不再開始新的撮合
+		if !inflight.Enter() {
+			return
+		}
+		err = pubsubMatchOpenPosition.MatchOpenPosition(ctx, &mqModels.OpenPositionModel{
+			OpenPositionModel: rabbitmq.OpenPositionModel{
+				ID:           p.OrderID,
+				MemberID:     p.MemberID,
+				ExchangeCode: p.ExchangeCode,
+				ProductCode:  p.ProductCode,
+				TradeType:    rabbitmq.TradeType(p.TradeType),
+				Amount:       p.Amount,
+			},
+			OrderType:   p.OrderType,
+			LimitPrice:  decimal.NewNullDecimal(p.LimitPrice),
+			TimeInForce: p.TimeInForce,
+			ExpireAt:    expireAt,
+		})
+		inflight.Leave()
+		if err != nil {
+			logging.Error(ctx, "[MatchOpenPosition] failed to match pendingOrder [%d]: %v", p.OrderID, err)
+		}
+	}
 }
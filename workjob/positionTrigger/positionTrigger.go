@@ -0,0 +1,267 @@
+package positionTrigger
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	common "github.com/paper-trade-chatbot/be-common"
+	"github.com/paper-trade-chatbot/be-common/database"
+	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-match/dao/matchRecordDao"
+	"github.com/paper-trade-chatbot/be-match/dao/positionTriggerDao"
+	"github.com/paper-trade-chatbot/be-match/lib/forceClose"
+	"github.com/paper-trade-chatbot/be-match/lib/limitOrder"
+	"github.com/paper-trade-chatbot/be-match/lib/marketPrice"
+	"github.com/paper-trade-chatbot/be-match/lib/trailingStop"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/paper-trade-chatbot/be-match/service"
+	"github.com/paper-trade-chatbot/be-proto/position"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const checkInterval = 3 * time.Second
+
+// PositionTrigger 定期以最新報價檢查倉位的停損停利, 觸價時以關倉流程平倉
+func PositionTrigger(ctx context.Context) error {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			checkTriggers(ctx)
+		}
+	}
+}
+
+func checkTriggers(ctx context.Context) {
+	// 下游服務斷路時暫停, 等恢復後再處理
+	if !service.Available() {
+		return
+	}
+
+	db := database.GetDB()
+
+	triggers, err := positionTriggerDao.Gets(db, &positionTriggerDao.QueryModel{
+		TriggerStatus: dbModels.TriggerStatus_Active,
+	})
+	if err != nil {
+		logging.Error(ctx, "[PositionTrigger] failed to get triggers: %v", err)
+		return
+	}
+
+	positionIDs := []uint64{}
+	positionTriggers := map[uint64][]dbModels.PositionTriggerModel{}
+	for _, t := range triggers {
+		if _, ok := positionTriggers[t.PositionID]; !ok {
+			positionIDs = append(positionIDs, t.PositionID)
+		}
+		positionTriggers[t.PositionID] = append(positionTriggers[t.PositionID], t)
+	}
+
+	prices := map[string]decimal.Decimal{}
+	for _, positionID := range positionIDs {
+		ts := positionTriggers[positionID]
+
+		key := fmt.Sprintf("%s:%s:%d", ts[0].ExchangeCode, ts[0].ProductCode, ts[0].TradeType)
+		unitPrice, ok := prices[key]
+		if !ok {
+			productRes, err := marketPrice.GetProduct(ctx, ts[0].ExchangeCode, ts[0].ProductCode)
+			if err != nil {
+				logging.Warn(ctx, "[PositionTrigger] failed to get product [%s][%s]: %v", ts[0].ExchangeCode, ts[0].ProductCode, err)
+				continue
+			}
+			unitPrice, err = marketPrice.GetPrice(ctx, productRes.Id, ts[0].TradeType, dbModels.TransactionType_ClosePosition)
+			if err != nil {
+				logging.Warn(ctx, "[PositionTrigger] failed to get price [%s][%s]: %v", ts[0].ExchangeCode, ts[0].ProductCode, err)
+				continue
+			}
+			prices[key] = unitPrice
+		}
+
+		for i := range ts {
+			if ts[i].TriggerType == dbModels.TriggerType_TrailingStop {
+				if err := trail(ctx, &ts[i], unitPrice); err != nil {
+					logging.Warn(ctx, "[PositionTrigger] failed to update watermark of trigger [%d]: %v", ts[i].ID, err)
+					continue
+				}
+			}
+			if isTriggered(&ts[i], unitPrice) {
+				fire(ctx, &ts[i], unitPrice)
+				break
+			}
+		}
+	}
+}
+
+// trail 以最新報價推動水位, 並將移動停損目前的停損價放回TriggerPrice
+func trail(ctx context.Context, t *dbModels.PositionTriggerModel, unitPrice decimal.Decimal) error {
+	watermark, err := trailingStop.UpdateWatermark(ctx, t.ID, t.TradeType, unitPrice)
+	if err != nil {
+		return err
+	}
+	t.TriggerPrice = trailingStop.StopPrice(t.TrailMode, t.TrailValue.Decimal, t.TradeType, watermark)
+	return nil
+}
+
+// isTriggered 多倉價格跌破停損或漲破停利時觸發, 空倉相反
+func isTriggered(t *dbModels.PositionTriggerModel, unitPrice decimal.Decimal) bool {
+	switch t.TriggerType {
+	case dbModels.TriggerType_StopLoss, dbModels.TriggerType_TrailingStop:
+		if t.TradeType == dbModels.TradeType_Buy {
+			return unitPrice.LessThanOrEqual(t.TriggerPrice)
+		}
+		return unitPrice.GreaterThanOrEqual(t.TriggerPrice)
+	case dbModels.TriggerType_TakeProfit:
+		if t.TradeType == dbModels.TradeType_Buy {
+			return unitPrice.GreaterThanOrEqual(t.TriggerPrice)
+		}
+		return unitPrice.LessThanOrEqual(t.TriggerPrice)
+	}
+	return false
+}
+
+// errNotMarketable 停利附加單的限價以成交價模型計算尚未可成交, 不建立關倉單
+var errNotMarketable = errors.New("limit price not marketable")
+
+func fire(ctx context.Context, t *dbModels.PositionTriggerModel, unitPrice decimal.Decimal) {
+	db := database.GetDB()
+
+	matchRecord, err := closeByTrigger(ctx, db, t)
+	if errors.Is(err, errNotMarketable) {
+		return
+	}
+	logging.Info(ctx, "[PositionTrigger] trigger [%d] type [%d] of position [%d] hit at %s", t.ID, t.TriggerType, t.PositionID, unitPrice.String())
+	if errors.Is(err, common.ErrNoSuchPosition) {
+		// 倉位已被關閉, 不需再監控
+		if err := cancelByPosition(db, t.PositionID); err != nil {
+			logging.Error(ctx, "[PositionTrigger] failed to cancel triggers of position [%d]: %v", t.PositionID, err)
+		}
+		return
+	}
+	if matchRecord == nil {
+		logging.Warn(ctx, "[PositionTrigger] position [%d] not closable now, retry later: %v", t.PositionID, err)
+		return
+	}
+	if err != nil {
+		// 平倉失敗時保持監控, 下次觸價再試, 失敗的關倉單不記錄在觸發上
+		logging.Error(ctx, "[PositionTrigger] failed to close position [%d] by order [%d]: %v", t.PositionID, matchRecord.OrderID, err)
+		return
+	}
+
+	triggerStatus := dbModels.TriggerStatus_Triggered
+	closeOrderID := sql.NullInt64{Valid: true, Int64: int64(matchRecord.OrderID)}
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := positionTriggerDao.Modify(tx, t, &positionTriggerDao.UpdateModel{
+			TriggerStatus:  &triggerStatus,
+			TriggerPrice:   &t.TriggerPrice,
+			TriggeredPrice: &decimal.NullDecimal{Valid: true, Decimal: unitPrice},
+			CloseOrderID:   &closeOrderID,
+			ClosePrice:     &matchRecord.ClosePrice,
+			TriggeredAt:    &sql.NullTime{Valid: true, Time: time.Now()},
+		}); err != nil {
+			return err
+		}
+
+		if !t.MatchRecordID.Valid {
+			return nil
+		}
+
+		// 附加單成交, 記錄在該附加單的撮合紀錄上
+		matchStatus := dbModels.MatchStatus_Finished
+		if err := matchRecordDao.Modify(tx, &dbModels.MatchRecordModel{ID: uint64(t.MatchRecordID.Int64)}, &matchRecordDao.UpdateModel{
+			MatchStatus: &matchStatus,
+			ClosePrice:  &matchRecord.ClosePrice,
+			LegOrderID:  &closeOrderID,
+		}); err != nil {
+			return err
+		}
+
+		// 二擇一, 同一母單的另一張附加單一併取消
+		if t.ParentOrderID.Valid {
+			if err := positionTriggerDao.CancelByParentOrder(tx, uint64(t.ParentOrderID.Int64)); err != nil {
+				return err
+			}
+			return matchRecordDao.CancelLegsByOrder(tx, uint64(t.ParentOrderID.Int64))
+		}
+		return nil
+	}); err != nil {
+		logging.Error(ctx, "[PositionTrigger] failed to Modify trigger [%d]: %v", t.ID, err)
+	}
+
+	if t.TriggerType == dbModels.TriggerType_TrailingStop {
+		if err := trailingStop.ClearWatermark(ctx, t.ID); err != nil {
+			logging.Warn(ctx, "[PositionTrigger] failed to clear watermark of trigger [%d]: %v", t.ID, err)
+		}
+	}
+
+	// 附加單可能只平部分倉位, 確認倉位已全部平倉後才取消其他觸發
+	closed, err := isPositionClosed(ctx, t.PositionID)
+	if err != nil {
+		logging.Warn(ctx, "[PositionTrigger] failed to get position [%d]: %v", t.PositionID, err)
+		return
+	}
+	if closed {
+		if err := cancelByPosition(db, t.PositionID); err != nil {
+			logging.Error(ctx, "[PositionTrigger] failed to cancel triggers of position [%d]: %v", t.PositionID, err)
+		}
+	}
+}
+
+// closeByTrigger 停利附加單以限價平倉附加單的數量, 其餘觸發以市價平整個倉位
+// 限價以與撮合相同的成交價模型計算未可成交時回傳errNotMarketable, 避免報價在觸價附近時反覆建立失敗的關倉單
+func closeByTrigger(ctx context.Context, db *gorm.DB, t *dbModels.PositionTriggerModel) (*dbModels.MatchRecordModel, error) {
+	if t.MatchRecordID.Valid {
+		leg, err := matchRecordDao.Get(db, &matchRecordDao.QueryModel{
+			ID: uint64(t.MatchRecordID.Int64),
+		})
+		if err != nil {
+			logging.Error(ctx, "[PositionTrigger] failed to get leg matchRecord [%d]: %v", t.MatchRecordID.Int64, err)
+			return nil, err
+		}
+		if leg != nil && leg.OrderType == dbModels.OrderType_Limit && leg.LimitPrice.Valid {
+			productRes, err := marketPrice.GetProduct(ctx, t.ExchangeCode, t.ProductCode)
+			if err != nil {
+				logging.Warn(ctx, "[PositionTrigger] failed to get product [%s][%s]: %v", t.ExchangeCode, t.ProductCode, err)
+				return nil, err
+			}
+			price, err := marketPrice.GetExecutionPrice(ctx, db, productRes, t.TradeType, dbModels.TransactionType_ClosePosition, leg.Amount)
+			if err != nil {
+				logging.Warn(ctx, "[PositionTrigger] failed to get execution price [%s][%s]: %v", t.ExchangeCode, t.ProductCode, err)
+				return nil, err
+			}
+			if !limitOrder.IsMarketable(t.TradeType, dbModels.TransactionType_ClosePosition, price, leg.LimitPrice.Decimal) {
+				return nil, errNotMarketable
+			}
+			return forceClose.ClosePositionAtLimit(ctx, t.PositionID, leg.Amount, leg.LimitPrice.Decimal, dbModels.TransactionType_ClosePosition)
+		}
+	}
+	return forceClose.ClosePosition(ctx, t.PositionID, dbModels.TransactionType_ClosePosition)
+}
+
+// isPositionClosed 倉位不存在或已關閉時回傳true
+func isPositionClosed(ctx context.Context, positionID uint64) (bool, error) {
+	positionRes, err := service.Impl.PositionIntf.GetPositions(ctx, &position.GetPositionsReq{
+		Id: []uint64{positionID},
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(positionRes.Positions) == 0 || positionRes.Positions[0].Status != position.PositionStatus_PositionStatus_Open, nil
+}
+
+// cancelByPosition 取消倉位上所有監控中的觸發及待成交的附加單
+func cancelByPosition(db *gorm.DB, positionID uint64) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := positionTriggerDao.CancelByPosition(tx, positionID); err != nil {
+			return err
+		}
+		return matchRecordDao.CancelLegsByPosition(tx, positionID)
+	})
+}
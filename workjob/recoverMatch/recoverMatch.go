@@ -0,0 +1,416 @@
+package recoverMatch
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/paper-trade-chatbot/be-common/database"
+	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-match/dao/matchRecordDao"
+	"github.com/paper-trade-chatbot/be-match/dao/pendingOrderDao"
+	"github.com/paper-trade-chatbot/be-match/dao/positionMarginDao"
+	"github.com/paper-trade-chatbot/be-match/lib/inflight"
+	"github.com/paper-trade-chatbot/be-match/lib/matchError"
+	"github.com/paper-trade-chatbot/be-match/lib/matchLock"
+	"github.com/paper-trade-chatbot/be-match/models/dbModels"
+	"github.com/paper-trade-chatbot/be-match/pubsub/matchEvent"
+	"github.com/paper-trade-chatbot/be-match/service"
+	"github.com/paper-trade-chatbot/be-proto/general"
+	"github.com/paper-trade-chatbot/be-proto/order"
+	"github.com/paper-trade-chatbot/be-proto/position"
+	"github.com/paper-trade-chatbot/be-proto/wallet"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+const (
+	checkInterval = time.Minute
+	// staleAfter 撮合紀錄超過此時間未更新才視為中斷, 避免搶走仍在撮合中的訂單
+	staleAfter = 5 * time.Minute
+	pageSize   = 100
+)
+
+// RecoverMatch 啟動時及之後定期接續或補償中斷的撮合
+// 依撮合紀錄的步驟判斷: 尚未扣款的直接失敗, 已扣款的依訂單狀態接續完成或回滾扣款
+func RecoverMatch(ctx context.Context) error {
+	recoverSagas(ctx)
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			recoverSagas(ctx)
+		}
+	}
+}
+
+func recoverSagas(ctx context.Context) {
+	// 下游服務斷路時暫停, 等恢復後再處理
+	if !service.Available() {
+		return
+	}
+
+	db := database.GetDB()
+
+	updatedBefore := time.Now().Add(-staleAfter)
+	legType := dbModels.LegType_None
+	matchRecords, err := matchRecordDao.Gets(db, &matchRecordDao.QueryModel{
+		MatchStatus:   dbModels.MatchStatus_Pending,
+		LegType:       &legType,
+		UpdatedBefore: &updatedBefore,
+	})
+	if err != nil {
+		logging.Error(ctx, "[RecoverMatch] failed to get matchRecords: %v", err)
+		return
+	}
+
+	for i := range matchRecords {
+		// 關閉服務中不再開始新的補償
+		if !inflight.Enter() {
+			return
+		}
+		err := recoverSaga(ctx, db, &matchRecords[i])
+		inflight.Leave()
+		if err != nil {
+			logging.Error(ctx, "[RecoverMatch] failed to recover matchRecord [%d]: %v", matchRecords[i].ID, err)
+		}
+	}
+}
+
+func recoverSaga(ctx context.Context, db *gorm.DB, matchRecord *dbModels.MatchRecordModel) error {
+
+	// 與撮合取相同的鎖, 取得後重新讀取撮合紀錄, 確認沒有仍在進行中的撮合
+	keys := []string{matchLock.MemberKey(matchRecord.MemberID)}
+	if matchRecord.TransactionType != dbModels.TransactionType_OpenPosition && matchRecord.PositionID.Valid {
+		keys = append(keys, matchLock.PositionKey(uint64(matchRecord.PositionID.Int64)))
+	}
+	locks, err := matchLock.AcquireAll(ctx, matchRecord.OrderID, keys...)
+	if err != nil {
+		return err
+	}
+	defer locks.Release()
+
+	matchRecord, err = matchRecordDao.Get(db, &matchRecordDao.QueryModel{
+		ID: matchRecord.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if matchRecord == nil || matchRecord.MatchStatus != dbModels.MatchStatus_Pending ||
+		time.Since(matchRecord.UpdatedAt) < staleAfter {
+		return nil
+	}
+
+	// 掛單中的訂單由掛單撮合處理, 撮合中斷的掛單一併接續或補償
+	pendingOrder, err := pendingOrderDao.Get(db, &pendingOrderDao.QueryModel{
+		OrderID:         matchRecord.OrderID,
+		TransactionType: matchRecord.TransactionType,
+	})
+	if err != nil {
+		return err
+	}
+	if pendingOrder != nil && pendingOrder.PendingStatus == dbModels.PendingStatus_Pending {
+		return nil
+	}
+	if pendingOrder != nil && pendingOrder.PendingStatus != dbModels.PendingStatus_Processing {
+		pendingOrder = nil
+	}
+
+	logging.Warn(ctx, "[RecoverMatch] matchRecord [%d] of order [%d] interrupted at step [%d].", matchRecord.ID, matchRecord.OrderID, matchRecord.SagaStep)
+
+	if err := locks.Check(); err != nil {
+		return err
+	}
+
+	switch matchRecord.SagaStep {
+	case dbModels.SagaStep_Transacted, dbModels.SagaStep_Matched:
+	default:
+		// 扣款後寫入步驟前中斷時撮合紀錄沒有交易id, 以交易備註找回已扣的款項回滾
+		if err := rollbackUnrecorded(ctx, matchRecord); err != nil {
+			return err
+		}
+		if pendingOrder != nil {
+			// 尚未扣款的掛單放回掛單中, 由掛單撮合重新撮合
+			pendingStatus := dbModels.PendingStatus_Pending
+			if err := pendingOrderDao.Modify(db, pendingOrder, &pendingOrderDao.UpdateModel{
+				PendingStatus: &pendingStatus,
+			}); err != nil {
+				return err
+			}
+			logging.Info(ctx, "[RecoverMatch] pendingOrder [%d] of order [%d] released.", pendingOrder.ID, matchRecord.OrderID)
+			return nil
+		}
+		// 尚未扣款, 直接將訂單失敗
+		matchStatus := dbModels.MatchStatus_Failed
+		return fail(ctx, db, matchRecord, nil, &matchRecordDao.UpdateModel{
+			MatchStatus: &matchStatus,
+		})
+	}
+
+	res, err := service.Impl.OrderIntf.GetOrders(ctx, &order.GetOrdersReq{
+		Id: []uint64{matchRecord.OrderID},
+	})
+	if err != nil {
+		return err
+	}
+	if len(res.Orders) > 0 && res.Orders[0].OrderStatus == order.OrderStatus_OrderStatus_Finished {
+		return resume(ctx, db, matchRecord, pendingOrder, res.Orders[0])
+	}
+	return compensate(ctx, db, matchRecord, pendingOrder)
+}
+
+// resume 訂單服務已完成訂單, 補上撮合後的紀錄
+func resume(ctx context.Context, db *gorm.DB, matchRecord *dbModels.MatchRecordModel, pendingOrder *dbModels.PendingOrderModel, o *order.Order) error {
+	unitPrice := decimal.Zero
+	if o.UnitPrice != nil {
+		p, err := decimal.NewFromString(*o.UnitPrice)
+		if err != nil {
+			return err
+		}
+		unitPrice = p
+	}
+
+	matchStatus := dbModels.MatchStatus_Finished
+	sagaStep := dbModels.SagaStep_Finished
+	update := &matchRecordDao.UpdateModel{
+		MatchStatus: &matchStatus,
+		SagaStep:    &sagaStep,
+	}
+	price := decimal.NewNullDecimal(unitPrice)
+
+	if matchRecord.TransactionType == dbModels.TransactionType_OpenPosition {
+		positionID := sql.NullInt64{Valid: true, Int64: int64(o.PositionID)}
+		update.PositionID = &positionID
+		update.OpenPrice = &price
+
+		if _, err := positionMarginDao.New(db, &dbModels.PositionMarginModel{
+			PositionID:   o.PositionID,
+			MemberID:     matchRecord.MemberID,
+			ExchangeCode: matchRecord.ExchangeCode,
+			ProductCode:  matchRecord.ProductCode,
+			TradeType:    matchRecord.TradeType,
+			MarginStatus: dbModels.MarginStatus_Open,
+			Leverage:     matchRecord.Leverage,
+			OpenPrice:    unitPrice,
+			Amount:       matchRecord.Amount,
+			Margin:       matchRecord.Margin.Decimal,
+		}); err != nil {
+			logging.Error(ctx, "[RecoverMatch] failed to new positionMargin [%d]: %v", o.PositionID, err)
+		}
+	} else {
+		update.ClosePrice = &price
+
+		if err := releaseMargin(db, matchRecord); err != nil {
+			logging.Error(ctx, "[RecoverMatch] failed to release positionMargin [%d]: %v", matchRecord.PositionID.Int64, err)
+		}
+	}
+
+	matchRecord.MatchStatus = matchStatus
+	matchRecord.SagaStep = sagaStep
+	if update.PositionID != nil {
+		matchRecord.PositionID = *update.PositionID
+		matchRecord.OpenPrice = price
+	} else {
+		matchRecord.ClosePrice = price
+	}
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := matchRecordDao.Modify(tx, matchRecord, update); err != nil {
+			return err
+		}
+		if err := closePendingOrder(tx, pendingOrder, dbModels.PendingStatus_Filled); err != nil {
+			return err
+		}
+		return matchEvent.Finished(tx, matchRecord, nil)
+	}); err != nil {
+		return err
+	}
+
+	if _, err := service.Impl.OrderIntf.UpdateOrderProcess(ctx, &order.UpdateOrderProcessReq{
+		Id:           matchRecord.OrderID,
+		OrderProcess: order.OrderProcess_OrderProcess_Finished,
+	}); err != nil {
+		logging.Error(ctx, "[RecoverMatch] failed to Update OrderProcess [%d]: %v", matchRecord.OrderID, err)
+	}
+
+	logging.Info(ctx, "[RecoverMatch] matchRecord [%d] resumed.", matchRecord.ID)
+	return nil
+}
+
+// releaseMargin 與關倉撮合相同, 扣除平倉數量及釋放的保證金
+func releaseMargin(db *gorm.DB, matchRecord *dbModels.MatchRecordModel) error {
+	positionMargin, err := positionMarginDao.Get(db, &positionMarginDao.QueryModel{
+		PositionID:   uint64(matchRecord.PositionID.Int64),
+		MarginStatus: dbModels.MarginStatus_Open,
+	})
+	if err != nil {
+		return err
+	}
+	if positionMargin == nil {
+		return nil
+	}
+
+	remainAmount := positionMargin.Amount.Sub(matchRecord.Amount)
+	remainMargin := positionMargin.Margin.Sub(matchRecord.Margin.Decimal)
+	marginStatus := dbModels.MarginStatus_Open
+	if !remainAmount.IsPositive() {
+		marginStatus = dbModels.MarginStatus_Released
+		remainAmount = decimal.Zero
+		remainMargin = decimal.Zero
+	}
+	return positionMarginDao.Modify(db, positionMargin, &positionMarginDao.UpdateModel{
+		MarginStatus: &marginStatus,
+		Amount:       &remainAmount,
+		Margin:       &remainMargin,
+	})
+}
+
+// closePendingOrder 撮合中斷的掛單隨撮合紀錄結束
+func closePendingOrder(tx *gorm.DB, pendingOrder *dbModels.PendingOrderModel, pendingStatus dbModels.PendingStatus) error {
+	if pendingOrder == nil {
+		return nil
+	}
+	return pendingOrderDao.Modify(tx, pendingOrder, &pendingOrderDao.UpdateModel{
+		PendingStatus: &pendingStatus,
+	})
+}
+
+// compensate 訂單未完成, 回滾已扣的款項後將訂單失敗
+func compensate(ctx context.Context, db *gorm.DB, matchRecord *dbModels.MatchRecordModel, pendingOrder *dbModels.PendingOrderModel) error {
+	transactionID, feeTransactionID := matchRecord.TransactionID, matchRecord.FeeTransactionID
+	for _, transactionID := range []*sql.NullInt64{&matchRecord.TransactionID, &matchRecord.FeeTransactionID} {
+		if !transactionID.Valid {
+			continue
+		}
+		if _, err := service.Impl.WalletIntf.RollbackTransaction(ctx, &wallet.RollbackTransactionReq{
+			Id:           uint64(transactionID.Int64),
+			RollbackerID: matchRecord.MemberID,
+		}); err != nil {
+			return err
+		}
+		// 已回滾的交易id先清掉, 下次補償時不會重複回滾
+		cleared := sql.NullInt64{}
+		update := &matchRecordDao.UpdateModel{TransactionID: &cleared}
+		if transactionID == &matchRecord.FeeTransactionID {
+			update = &matchRecordDao.UpdateModel{FeeTransactionID: &cleared}
+		}
+		if err := matchRecordDao.Modify(db, matchRecord, update); err != nil {
+			return err
+		}
+		*transactionID = cleared
+	}
+
+	// 補償完成後保留回滾過的交易id供對帳
+	matchStatus := dbModels.MatchStatus_Rollbacked
+	sagaStep := dbModels.SagaStep_Compensated
+	return fail(ctx, db, matchRecord, pendingOrder, &matchRecordDao.UpdateModel{
+		MatchStatus:      &matchStatus,
+		SagaStep:         &sagaStep,
+		TransactionID:    &transactionID,
+		FeeTransactionID: &feeTransactionID,
+	})
+}
+
+// fail 與撮合失敗相同, 將訂單失敗並停止待關倉的倉位
+func fail(ctx context.Context, db *gorm.DB, matchRecord *dbModels.MatchRecordModel, pendingOrder *dbModels.PendingOrderModel, update *matchRecordDao.UpdateModel) error {
+	matchRecord.MatchStatus = *update.MatchStatus
+	if update.TransactionID != nil {
+		matchRecord.TransactionID = *update.TransactionID
+	}
+	if update.FeeTransactionID != nil {
+		matchRecord.FeeTransactionID = *update.FeeTransactionID
+	}
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := matchRecordDao.Modify(tx, matchRecord, update); err != nil {
+			return err
+		}
+		if err := closePendingOrder(tx, pendingOrder, dbModels.PendingStatus_Failed); err != nil {
+			return err
+		}
+		return matchEvent.Finished(tx, matchRecord, matchError.ErrMatchInterrupted)
+	}); err != nil {
+		return err
+	}
+
+	failCode := uint64(status.Code(matchError.ErrMatchInterrupted))
+	s, _ := status.FromError(matchError.ErrMatchInterrupted)
+	remark := s.Message()
+	if _, err := service.Impl.OrderIntf.FailOrder(ctx, &order.FailOrderReq{
+		Id:       matchRecord.OrderID,
+		FailCode: &failCode,
+		Remark:   &remark,
+	}); err != nil {
+		logging.Error(ctx, "[RecoverMatch] failed to FailOrder [%d]: %v", matchRecord.OrderID, err)
+	}
+	if matchRecord.TransactionType != dbModels.TransactionType_OpenPosition && matchRecord.PositionID.Valid {
+		if _, err := service.Impl.PositionIntf.StopPendingPosition(ctx, &position.StopPendingPositionReq{
+			Id: uint64(matchRecord.PositionID.Int64),
+		}); err != nil {
+			logging.Error(ctx, "[RecoverMatch] StopPendingPosition failed: %v", err)
+		}
+	}
+	if _, err := service.Impl.OrderIntf.UpdateOrderProcess(ctx, &order.UpdateOrderProcessReq{
+		Id:           matchRecord.OrderID,
+		OrderProcess: order.OrderProcess_OrderProcess_Failed,
+	}); err != nil {
+		logging.Error(ctx, "[RecoverMatch] failed to Update OrderProcess [%d]: %v", matchRecord.OrderID, err)
+	}
+
+	logging.Info(ctx, "[RecoverMatch] matchRecord [%d] closed with status [%d].", matchRecord.ID, *update.MatchStatus)
+	return nil
+}
+
+// rollbackUnrecorded 回滾撮合紀錄建立後以"order %d"及"fee of order %d"備註扣款成功的交易
+func rollbackUnrecorded(ctx context.Context, matchRecord *dbModels.MatchRecordModel) error {
+	action := wallet.Action_Action_OPEN
+	if matchRecord.TransactionType != dbModels.TransactionType_OpenPosition {
+		action = wallet.Action_Action_CLOSE
+	}
+	remarks := map[string]bool{
+		fmt.Sprintf("order %d", matchRecord.OrderID):        true,
+		fmt.Sprintf("fee of order %d", matchRecord.OrderID): true,
+	}
+
+	transactions := []*wallet.TransactionRecord{}
+	createdFrom := matchRecord.CreatedAt.Unix()
+	for page := int32(1); ; page++ {
+		res, err := service.Impl.WalletIntf.GetTransactionRecords(ctx, &wallet.GetTransactionRecordsReq{
+			MemberID:    &matchRecord.MemberID,
+			Action:      []wallet.Action{action},
+			CreatedFrom: &createdFrom,
+			Pagination: &general.Pagination{
+				Page:     page,
+				PageSize: pageSize,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		for _, t := range res.Records {
+			if t.Remark != nil && remarks[*t.Remark] && t.Status == wallet.Status_Status_SUCCESS {
+				transactions = append(transactions, t)
+			}
+		}
+		if len(res.Records) < pageSize {
+			break
+		}
+	}
+
+	for _, t := range transactions {
+		if _, err := service.Impl.WalletIntf.RollbackTransaction(ctx, &wallet.RollbackTransactionReq{
+			Id:           t.Id,
+			RollbackerID: matchRecord.MemberID,
+		}); err != nil {
+			logging.Error(ctx, "[RecoverMatch] failed to RollbackTransaction [%d] of order [%d]: %v", t.Id, matchRecord.OrderID, err)
+			return err
+		}
+		logging.Warn(ctx, "[RecoverMatch] unrecorded transaction [%d] of order [%d] rolled back.", t.Id, matchRecord.OrderID)
+	}
+	return nil
+}
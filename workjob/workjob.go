@@ -6,9 +6,16 @@ import (
 	"runtime"
 	"runtime/debug"
 	"strings"
+	"sync"
 
 	"github.com/gofrs/uuid"
 	"github.com/paper-trade-chatbot/be-common/logging"
+	"github.com/paper-trade-chatbot/be-match/workjob/marginMonitor"
+	"github.com/paper-trade-chatbot/be-match/workjob/matchClosePosition"
+	"github.com/paper-trade-chatbot/be-match/workjob/matchEventRelay"
+	"github.com/paper-trade-chatbot/be-match/workjob/matchOpenPosition"
+	"github.com/paper-trade-chatbot/be-match/workjob/positionTrigger"
+	"github.com/paper-trade-chatbot/be-match/workjob/recoverMatch"
 )
 
 type Job struct {
@@ -16,36 +23,51 @@ type Job struct {
 	Cancel  context.CancelFunc
 }
 
-var jobs map[string]*Job
+// wg 關閉服務時等待所有workjob結束, 才關閉DB及Redis連線
+var wg sync.WaitGroup
+
+var jobs = map[string]*Job{
+	"matchOpenPosition":  {Workjob: matchOpenPosition.MatchOpenPosition},
+	"matchClosePosition": {Workjob: matchClosePosition.MatchClosePosition},
+	"positionTrigger":    {Workjob: positionTrigger.PositionTrigger},
+	"marginMonitor":      {Workjob: marginMonitor.MarginMonitor},
+	"recoverMatch":       {Workjob: recoverMatch.RecoverMatch},
+	"matchEventRelay":    {Workjob: matchEventRelay.MatchEventRelay},
+}
 
 func Initialize(ctx context.Context) {
 
 	for _, j := range jobs {
-		go work(ctx, j)
+		workjobID, _ := uuid.NewV4()
+		ctxWithValue := context.WithValue(ctx, logging.ContextKeyRequestId, workjobID.String())
+		ctxCancel, cancel := context.WithCancel(ctxWithValue)
+		j.Cancel = cancel
+
+		wg.Add(1)
+		go func(j *Job) {
+			defer wg.Done()
+			defer cancel()
+			work(ctxWithValue, ctxCancel, j)
+		}(j)
 	}
 }
 
+// Finalize 取消所有workjob並等待結束
 func Finalize(ctx context.Context) {
 	for _, j := range jobs {
-		if j != nil {
+		if j != nil && j.Cancel != nil {
 			j.Cancel()
 		}
 	}
+	wg.Wait()
 }
 
-func work(ctx context.Context, job *Job) {
-
-	workjobID, _ := uuid.NewV4()
-	ctxWithValue := context.WithValue(ctx, logging.ContextKeyRequestId, workjobID.String())
+func work(ctxWithValue, ctxCancel context.Context, job *Job) {
 
 	funcName := strings.Split(runtime.FuncForPC(reflect.ValueOf(job.Workjob).Pointer()).Name(), "/")
 	logging.Info(ctxWithValue, "[Workjob] start %s", funcName[len(funcName)-1])
 	key := "Workjob:" + funcName[len(funcName)-1]
 
-	ctxCancel, cancel := context.WithCancel(ctxWithValue)
-	defer cancel()
-	job.Cancel = cancel
-
 	select {
 	case <-ctxCancel.Done():
 		logging.Error(ctxCancel, "[Workjob] %s cancelled error: %v", key, ctxCancel.Err())